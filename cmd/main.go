@@ -1,19 +1,118 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"log/slog"
 	"os"
+	"time"
 
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/logging"
+	"github.com/Luiz-F3lipe/snapTUI/internal/notify"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
 	"github.com/Luiz-F3lipe/snapTUI/internal/ui"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	app := ui.NewApp()
+	settingsPath := flag.String("config", config.DefaultSettingsPath(), "caminho do arquivo de configuração")
+	profileName := flag.String("profile", "", "perfil de conexão a utilizar")
+	notifyTest := flag.Bool("notify-test", false, "envia uma notificação de teste para os destinos do perfil e sai")
+	logLevel := flag.String("log-level", "", "nível de log: debug, info, warn ou error")
+	logFile := flag.String("log-file", "", "caminho do arquivo de log")
+	connectTimeout := flag.Int("connect-timeout", 0, "tempo limite de conexão em segundos")
+	allowRestore := flag.Bool("allow-restore", false, "permite restaurar em perfis marcados como produção")
+	flag.Parse()
+
+	settings, err := config.LoadSettings(*settingsPath)
+	if err != nil {
+		fmt.Printf("Erro ao carregar configuração: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *logLevel != "" {
+		settings.Log.Level = *logLevel
+	}
+	if *logFile != "" {
+		settings.Log.Path = *logFile
+	}
+
+	logger, closer, err := logging.New(logging.Options{
+		Path:       settings.Log.Path,
+		Level:      settings.Log.Level,
+		Format:     settings.Log.Format,
+		MaxSizeMB:  settings.Log.MaxSizeMB,
+		MaxBackups: settings.Log.MaxBackups,
+	})
+	if err != nil {
+		fmt.Printf("Erro ao configurar log: %v\n", err)
+		os.Exit(1)
+	}
+	defer closer.Close()
+
+	profile, err := settings.Profile(*profileName)
+	if err != nil {
+		fmt.Printf("Erro ao carregar perfil: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *connectTimeout > 0 {
+		profile.ConnectTimeout = *connectTimeout
+	}
+	if *allowRestore {
+		profile.AllowRestore = true
+	}
+
+	logger.Info("snapTUI started", "profile", profile.Name)
+
+	if *notifyTest {
+		code := sendTestNotification(profile, logger)
+		closer.Close()
+		os.Exit(code)
+	}
+
+	app := ui.NewApp(settings, profile, logger)
 	p := tea.NewProgram(app, tea.WithAltScreen())
 
-	if _, err := p.Run(); err != nil {
+	_, err = p.Run()
+	if closeErr := app.Close(); closeErr != nil {
+		logger.Error("failed to close database connections", "error", closeErr)
+	}
+	if err != nil {
+		logger.Error("application error", "error", err)
+		closer.Close()
 		fmt.Printf("Erro ao executar aplicação: %v\n", err)
 		os.Exit(1)
 	}
+	logger.Info("snapTUI finished")
+}
+
+// sendTestNotification sends a sample summary to every target of the profile
+func sendTestNotification(profile config.Profile, logger *slog.Logger) int {
+	now := time.Now()
+	summary := notify.NewSummary(profile.Name, profile.Host, types.BackupCompleteMsg{
+		Success:    1,
+		Filenames:  []string{"teste_" + now.Format("20060102_150405") + ".backup"},
+		StartedAt:  now,
+		FinishedAt: now,
+	})
+
+	// Force delivery regardless of the success/failure policies
+	targets := profile.Notifications.Targets
+	for i := range targets {
+		targets[i].OnSuccess = true
+	}
+
+	errs := notify.NewService(profile.Notifications, logger).Send(context.Background(), summary)
+	for _, err := range errs {
+		fmt.Printf("Erro: %v\n", err)
+	}
+	if len(errs) > 0 {
+		return 1
+	}
+
+	fmt.Printf("Notificação de teste enviada para %d destino(s)\n", len(targets))
+	return 0
 }
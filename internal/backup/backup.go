@@ -1,99 +1,173 @@
 package backup
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"log/slog"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/Luiz-F3lipe/snapTUI/internal/catalog"
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/database"
+	"github.com/Luiz-F3lipe/snapTUI/internal/logging"
 	"github.com/Luiz-F3lipe/snapTUI/internal/types"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 // Service handles backup operations
-type Service struct{}
+type Service struct {
+	logger  *slog.Logger
+	catalog *catalog.Service
 
-// NewService creates a new backup service
-func NewService() *Service {
-	return &Service{}
+	clientsMu sync.Mutex
+	clients   map[string][]ClientBinary // installed client tools by name, discovered once
 }
 
-// FindPgDump locates pg_dump executable
-func (s *Service) FindPgDump() (string, error) {
-	// Try to find pg_dump in PATH
-	pgDumpPath, err := exec.LookPath("pg_dump")
-	if err == nil {
-		return pgDumpPath, nil
-	}
-
-	// Common PostgreSQL paths on Linux
-	commonPaths := []string{
-		"/usr/bin/pg_dump",
-		"/usr/local/bin/pg_dump",
-		"/usr/pgsql-15/bin/pg_dump",
-		"/usr/pgsql-14/bin/pg_dump",
-		"/usr/pgsql-13/bin/pg_dump",
-		"/usr/pgsql-12/bin/pg_dump",
-		"/opt/postgresql/bin/pg_dump",
-		"/snap/bin/pg_dump",
-	}
-
-	for _, path := range commonPaths {
-		if _, err := os.Stat(path); err == nil {
-			return path, nil
-		}
-	}
+// NewService creates a new backup service that records runs in the given catalog
+func NewService(logger *slog.Logger, catalog *catalog.Service) *Service {
+	return &Service{logger: logger, catalog: catalog}
+}
 
-	return "", fmt.Errorf("pg_dump not found.\n\nTo install on Ubuntu/Debian: sudo apt install postgresql-client\nTo install on CentOS/RHEL: sudo yum install postgresql\nOr add pg_dump path to system PATH")
+// BackupDir returns the directory where backup files are written
+func (s *Service) BackupDir() (string, error) {
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to get executable path: %w", err)
+	}
+	return filepath.Dir(exePath), nil
 }
 
-// BackupDatabase performs backup of a single database
-func (s *Service) BackupDatabase(host, port, user, password, dbname string) (string, error) {
-	// Find pg_dump
-	pgDumpPath, err := s.FindPgDump()
+// BackupDatabase performs backup of a single database into the backup directory
+func (s *Service) BackupDatabase(conn types.DatabaseConnection, dbname string, dump config.PgDumpSettings) (string, error) {
+	// Get destination directory
+	exeDir, err := s.BackupDir()
 	if err != nil {
 		return "", err
 	}
 
-	// Get executable directory
-	exePath, err := os.Executable()
+	backupPath, err := s.dumpDatabase(exeDir, conn, dbname, "", dump)
 	if err != nil {
-		return "", fmt.Errorf("failed to get executable path: %w", err)
+		return "", err
+	}
+	return filepath.Base(backupPath), nil
+}
+
+// dumpDatabase runs pg_dump for dbname into dir and returns the path of the created file. A
+// non-empty schema limits the dump to that schema and is added to the file name.
+func (s *Service) dumpDatabase(dir string, conn types.DatabaseConnection, dbname, schema string, dump config.PgDumpSettings) (string, error) {
+	// Find pg_dump
+	pgDump, err := s.FindPgDump(dump, conn.ServerVersion)
+	if err != nil {
+		return "", err
 	}
-	exeDir := filepath.Dir(exePath)
 
 	// Create filename with timestamp
+	format := dump.FormatOrDefault()
 	timestamp := time.Now().Format("20060102_150405")
-	filename := fmt.Sprintf("%s_%s.backup", dbname, timestamp)
-	backupPath := filepath.Join(exeDir, filename)
+	filename := fmt.Sprintf("%s_%s%s", dbname, timestamp, dumpExtension(format))
+	if schema != "" {
+		filename = fmt.Sprintf("%s.%s_%s%s", dbname, schemaFileName(schema), timestamp, dumpExtension(format))
+	}
+	backupPath := filepath.Join(dir, filename)
 
-	// pg_dump command
-	cmd := exec.Command(pgDumpPath,
-		"--host", host,
+	// pg_dump command, through the SSH tunnel when one is open
+	host, port := conn.Address()
+	args := []string{
+		"--host", pgDump.DialHost(host),
 		"--port", port,
-		"--username", user,
+		"--username", conn.User,
 		"--no-password",
-		"--format", "custom",
-		"--file", backupPath,
-		dbname,
-	)
+		"--format", format,
+	}
+	// A containerized pg_dump cannot see the host filesystem, so its output is streamed back
+	var outFile *os.File
+	if pgDump.Containerized() {
+		if outFile, err = os.Create(backupPath); err != nil {
+			return "", fmt.Errorf("failed to create backup file: %w", err)
+		}
+		defer outFile.Close()
+	} else {
+		args = append(args, "--file", backupPath)
+	}
+	if schema != "" {
+		// Quoted, the pattern matches the name exactly: no case folding and no wildcards
+		args = append(args, "--schema", `"`+strings.ReplaceAll(schema, `"`, `""`)+`"`)
+	}
+	args = append(args, dump.Args...)
+	args = append(args, dbname)
+	cmd := pgDump.Command(args...)
 
-	// Set password environment variable
-	cmd.Env = append(os.Environ(), fmt.Sprintf("PGPASSWORD=%s", password))
+	// Set the password, SSL mode and timeout environment variables
+	cmd.Env = database.ClientEnv(conn)
+
+	var output bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &output, &output
+	if outFile != nil {
+		cmd.Stdout = outFile
+	}
+
+	logger := s.logger.With("host", conn.Host, "database", dbname)
+	if schema != "" {
+		logger = logger.With("schema", schema)
+	}
+	logger.Info("starting pg_dump", "command", logging.RedactArgs(cmd.Path, cmd.Args[1:]), "file", backupPath)
+	start := time.Now()
 
 	// Execute command
-	output, err := cmd.CombinedOutput()
+	err = cmd.Run()
+	if err == nil && outFile != nil {
+		err = outFile.Sync()
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to execute pg_dump for %s: %w\nOutput: %s", dbname, err, string(output))
+		if outFile != nil {
+			outFile.Close()
+			os.Remove(backupPath)
+		}
+		logger.Error("pg_dump failed", "duration", time.Since(start), "error", err, "stderr", logging.Redact(output.String()))
+		return "", fmt.Errorf("failed to execute pg_dump for %s: %w\nOutput: %s", dbname, err, output.String())
 	}
+	if output.Len() > 0 {
+		logger.Warn("pg_dump reported messages", "stderr", logging.Redact(output.String()))
+	}
+
+	logger.Info("pg_dump finished", "duration", time.Since(start), "file", backupPath)
+	return backupPath, nil
+}
 
-	return filename, nil
+// schemaFileName returns schema as used in file names. When characters had to be replaced, a
+// short hash of the real name keeps schemas such as "a b" and "a_b" from sharing a file.
+func schemaFileName(schema string) string {
+	safe := unsafeName.ReplaceAllString(schema, "_")
+	if safe == schema {
+		return safe
+	}
+	sum := sha256.Sum256([]byte(schema))
+	return safe + "-" + hex.EncodeToString(sum[:4])
+}
+
+// dumpExtension returns the backup file extension for a pg_dump format
+func dumpExtension(format string) string {
+	switch format {
+	case config.DumpFormatTar:
+		return ".tar"
+	case config.DumpFormatPlain:
+		return ".sql"
+	default:
+		return ".backup"
+	}
 }
 
 // PerformBackupCmd creates a command to perform backup operation
 func (s *Service) PerformBackupCmd(m types.Model) tea.Cmd {
 	return func() tea.Msg {
+		startedAt := time.Now()
+
 		// Count total databases
 		total := 0
 		for i := range m.Choices {
@@ -102,26 +176,119 @@ func (s *Service) PerformBackupCmd(m types.Model) tea.Cmd {
 			}
 		}
 
+		conn := m.Connection()
+
 		var errors []string
 		var filenames []string
 		successCount := 0
 
 		for i, db := range m.Choices {
 			if i > 0 { // Skip "All Databases" (index 0)
-				filename, err := s.BackupDatabase(m.Inputs[0], m.Inputs[1], m.Inputs[2], m.Inputs[3], db)
+				entry := catalog.Entry{
+					Profile:    m.ProfileName,
+					Host:       conn.Host,
+					Port:       conn.Port,
+					Database:   db,
+					Kind:       catalog.KindDump,
+					SourceSize: m.DatabaseInfo[db].Size,
+					StartedAt:  time.Now(),
+				}
+
+				filename, err := s.BackupDatabase(conn, db, m.PgDump)
 				if err != nil {
 					errors = append(errors, fmt.Sprintf("Error backing up %s: %v", db, err))
+					entry.Error = err.Error()
 				} else {
 					successCount++
 					filenames = append(filenames, filename)
+					entry.Success = true
 				}
+
+				if filename != "" {
+					if dir, err := s.BackupDir(); err == nil {
+						entry.Path = filepath.Join(dir, filename)
+					}
+				}
+				s.record(entry)
 			}
 		}
 
+		s.logger.Info("backup run finished", "success", successCount, "errors", len(errors), "duration", time.Since(startedAt))
+
 		return types.BackupCompleteMsg{
-			Success:   successCount,
-			Errors:    errors,
-			Filenames: filenames,
+			Success:    successCount,
+			Errors:     errors,
+			Filenames:  filenames,
+			StartedAt:  startedAt,
+			FinishedAt: time.Now(),
 		}
 	}
 }
+
+// PerformSchemaBackupCmd creates a command that dumps each schema of dbname into its own file,
+// recording every file in the catalog with its schema
+func (s *Service) PerformSchemaBackupCmd(m types.Model, dbname string, schemas []types.SchemaInfo) tea.Cmd {
+	return func() tea.Msg {
+		startedAt := time.Now()
+		conn := m.Connection()
+
+		var errors []string
+		var filenames []string
+		successCount := 0
+
+		dir, err := s.BackupDir()
+		if err != nil {
+			return types.BackupCompleteMsg{Errors: []string{err.Error()}, StartedAt: startedAt, FinishedAt: time.Now()}
+		}
+
+		for _, schema := range schemas {
+			entry := catalog.Entry{
+				Profile:    m.ProfileName,
+				Host:       conn.Host,
+				Port:       conn.Port,
+				Database:   dbname,
+				Schema:     schema.Name,
+				Kind:       catalog.KindDump,
+				SourceSize: schema.Size,
+				StartedAt:  time.Now(),
+			}
+
+			path, err := s.dumpDatabase(dir, conn, dbname, schema.Name, m.PgDump)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("Error backing up %s.%s: %v", dbname, schema.Name, err))
+				entry.Error = err.Error()
+			} else {
+				successCount++
+				filenames = append(filenames, filepath.Base(path))
+				entry.Success = true
+				entry.Path = path
+			}
+			s.record(entry)
+		}
+
+		s.logger.Info("schema backup run finished", "database", dbname, "success", successCount, "errors", len(errors),
+			"duration", time.Since(startedAt))
+
+		return types.BackupCompleteMsg{
+			Success:    successCount,
+			Errors:     errors,
+			Filenames:  filenames,
+			StartedAt:  startedAt,
+			FinishedAt: time.Now(),
+		}
+	}
+}
+
+// record stores a finished backup in the catalog
+func (s *Service) record(entry catalog.Entry) {
+	entry.FinishedAt = time.Now()
+	if entry.Path != "" {
+		if info, err := os.Stat(entry.Path); err == nil {
+			entry.OutputSize = info.Size()
+		}
+	}
+
+	if err := s.catalog.Record(entry); err != nil {
+		s.logger.Error("failed to record backup in catalog", "database", entry.Database, "error", err)
+	}
+}
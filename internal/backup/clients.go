@@ -0,0 +1,278 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+)
+
+// ClientBinary represents an installed PostgreSQL client program
+type ClientBinary struct {
+	Tool    string
+	Path    string
+	Version int // in server_version_num format, e.g. 160002
+
+	// Container execution: runtime CLI path and image, empty for local binaries
+	Runtime string
+	Image   string
+}
+
+// clientSearchPatterns lists directories where PostgreSQL clients are commonly installed
+var clientSearchPatterns = []string{
+	"/usr/lib/postgresql/*/bin",
+	"/usr/pgsql-*/bin",
+	"/usr/local/pgsql/bin",
+	"/opt/postgresql*/bin",
+	"/opt/homebrew/opt/postgresql@*/bin",
+	"/usr/local/opt/postgresql@*/bin",
+	"/Applications/Postgres.app/Contents/Versions/*/bin",
+	"/usr/bin",
+	"/usr/local/bin",
+	"/snap/bin",
+}
+
+// versionPattern extracts the version from "pg_dump (PostgreSQL) 16.2 (Ubuntu 16.2-1)"
+var versionPattern = regexp.MustCompile(`\(PostgreSQL\)\s+(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+// FindPgDump returns the pg_dump to use for the profile and server version
+func (s *Service) FindPgDump(settings config.PgDumpSettings, serverVersion int) (ClientBinary, error) {
+	return s.findTool("pg_dump", settings.Path, settings, serverVersion)
+}
+
+// FindPgRestore returns the pg_restore to use for the profile and server version
+func (s *Service) FindPgRestore(settings config.PgDumpSettings, serverVersion int) (ClientBinary, error) {
+	path := ""
+	if settings.Path != "" {
+		path = filepath.Join(filepath.Dir(settings.Path), "pg_restore")
+	}
+	return s.findTool("pg_restore", path, settings, serverVersion)
+}
+
+// FindPsql returns the psql to use for the profile and server version
+func (s *Service) FindPsql(settings config.PgDumpSettings, serverVersion int) (ClientBinary, error) {
+	path := ""
+	if settings.Path != "" {
+		path = filepath.Join(filepath.Dir(settings.Path), "psql")
+	}
+	return s.findTool("psql", path, settings, serverVersion)
+}
+
+// FindPgBasebackup returns the pg_basebackup to use for the profile and server version
+func (s *Service) FindPgBasebackup(settings config.PgDumpSettings, serverVersion int) (ClientBinary, error) {
+	path := ""
+	if settings.Path != "" {
+		path = filepath.Join(filepath.Dir(settings.Path), "pg_basebackup")
+	}
+	return s.findTool("pg_basebackup", path, settings, serverVersion)
+}
+
+// findTool resolves a client tool from an explicit path, the configured container runtime,
+// or the installed binaries, falling back to a container when none is compatible
+func (s *Service) findTool(tool, path string, settings config.PgDumpSettings, serverVersion int) (ClientBinary, error) {
+	switch {
+	case path != "":
+		return s.clientAt(tool, path, serverVersion)
+	case settings.Runtime == config.RuntimeDocker || settings.Runtime == config.RuntimePodman:
+		return s.containerClient(tool, settings.Runtime, settings, serverVersion)
+	}
+
+	client, err := s.FindClient(tool, serverVersion)
+	if err == nil || settings.Runtime == config.RuntimeLocal {
+		return client, err
+	}
+
+	container, containerErr := s.containerClient(tool, "", settings, serverVersion)
+	if containerErr != nil {
+		return ClientBinary{}, fmt.Errorf("%w\nOr install Docker or Podman to run %s in a container", err, tool)
+	}
+	s.logger.Warn("no compatible local client, using container", "tool", tool, "error", err)
+	return container, nil
+}
+
+// clientAt checks an explicitly configured client binary against the server version
+func (s *Service) clientAt(tool, path string, serverVersion int) (ClientBinary, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ClientBinary{}, fmt.Errorf("configured client %s not found: %w", path, err)
+	}
+	if info.IsDir() {
+		return ClientBinary{}, fmt.Errorf("configured client %s is a directory", path)
+	}
+
+	version, err := clientVersion(path)
+	if err != nil {
+		return ClientBinary{}, fmt.Errorf("failed to read version of %s: %w", path, err)
+	}
+	if serverVersion > 0 && !compatible(version, serverVersion) {
+		return ClientBinary{}, fmt.Errorf("configured client %s (%s) is not compatible with server PostgreSQL %s; "+
+			"point it to version %s or newer", path, FormatVersion(version), FormatVersion(serverVersion), MajorVersion(serverVersion))
+	}
+
+	s.logger.Debug("using configured client", "path", path, "version", version)
+	return ClientBinary{Tool: tool, Path: path, Version: version}, nil
+}
+
+// FindClient locates the installed client tool best suited to serverVersion: the oldest major
+// version that is not older than the server. A zero serverVersion selects the newest client.
+func (s *Service) FindClient(tool string, serverVersion int) (ClientBinary, error) {
+	clients := s.InstalledClients(tool)
+	if len(clients) == 0 {
+		s.logger.Error("client tool not found", "tool", tool)
+		return ClientBinary{}, fmt.Errorf("%s not found.\n\nTo install on Ubuntu/Debian: sudo apt install postgresql-client\nTo install on CentOS/RHEL: sudo yum install postgresql\nOr add %s path to system PATH", tool, tool)
+	}
+
+	if serverVersion == 0 {
+		newest := clients[len(clients)-1]
+		s.logger.Debug("selected client", "tool", tool, "path", newest.Path, "version", newest.Version)
+		return newest, nil
+	}
+
+	for _, client := range clients {
+		if compatible(client.Version, serverVersion) {
+			s.logger.Info("selected client", "tool", tool, "path", client.Path,
+				"version", FormatVersion(client.Version), "server_version", FormatVersion(serverVersion))
+			return client, nil
+		}
+	}
+
+	var found []string
+	for _, client := range clients {
+		found = append(found, fmt.Sprintf("%s (%s)", client.Path, FormatVersion(client.Version)))
+	}
+	major := MajorVersion(serverVersion)
+	s.logger.Error("no compatible client", "tool", tool, "server_version", serverVersion, "found", found)
+	return ClientBinary{}, fmt.Errorf("no %s compatible with server PostgreSQL %s.\n\nFound: %s\n\n"+
+		"Install a %s client version %s or newer:\n"+
+		"  Ubuntu/Debian: sudo apt install postgresql-client-%s\n"+
+		"  CentOS/RHEL:   sudo dnf install postgresql%s\n"+
+		"Or add its bin directory to system PATH",
+		tool, FormatVersion(serverVersion), strings.Join(found, ", "), tool, major, major, major)
+}
+
+// InstalledClients enumerates every installed copy of tool with its version, oldest first
+func (s *Service) InstalledClients(tool string) []ClientBinary {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	if clients, ok := s.clients[tool]; ok {
+		return clients
+	}
+
+	var dirs []string
+	dirs = append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+	for _, pattern := range clientSearchPatterns {
+		matches, _ := filepath.Glob(pattern)
+		dirs = append(dirs, matches...)
+	}
+
+	seen := make(map[string]bool)
+	var clients []ClientBinary
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		path := filepath.Join(dir, tool)
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		// Wrappers such as Debian's pg_wrapper resolve to the same binary
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			resolved = path
+		}
+		if seen[resolved] {
+			continue
+		}
+		seen[resolved] = true
+
+		version, err := clientVersion(path)
+		if err != nil {
+			s.logger.Warn("failed to read client version", "path", path, "error", err)
+			continue
+		}
+		clients = append(clients, ClientBinary{Tool: tool, Path: path, Version: version})
+	}
+
+	sort.SliceStable(clients, func(i, j int) bool {
+		return clients[i].Version < clients[j].Version
+	})
+
+	if s.clients == nil {
+		s.clients = make(map[string][]ClientBinary)
+	}
+	s.clients[tool] = clients
+	return clients
+}
+
+// clientVersion runs "<tool> --version" and parses the reported version
+func clientVersion(path string) (int, error) {
+	output, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		return 0, err
+	}
+	return ParseVersion(string(output))
+}
+
+// ParseVersion converts a "(PostgreSQL) X.Y" version string to server_version_num format
+func ParseVersion(s string) (int, error) {
+	match := versionPattern.FindStringSubmatch(s)
+	if match == nil {
+		return 0, fmt.Errorf("unrecognized version %q", strings.TrimSpace(s))
+	}
+
+	parts := make([]int, 3)
+	for i, part := range match[1:] {
+		if part != "" {
+			parts[i], _ = strconv.Atoi(part)
+		}
+	}
+
+	if parts[0] >= 10 {
+		return parts[0]*10000 + parts[1], nil
+	}
+	return parts[0]*10000 + parts[1]*100 + parts[2], nil
+}
+
+// MajorVersion returns the major version of a server_version_num value, e.g. "16" or "9.6"
+func MajorVersion(version int) string {
+	if version >= 100000 {
+		return strconv.Itoa(version / 10000)
+	}
+	return fmt.Sprintf("%d.%d", version/10000, version/100%100)
+}
+
+// FormatVersion renders a server_version_num value, e.g. "16.2" or "9.6.24"
+func FormatVersion(version int) string {
+	if version >= 100000 {
+		return fmt.Sprintf("%d.%d", version/10000, version%10000)
+	}
+	return fmt.Sprintf("%d.%d.%d", version/10000, version/100%100, version%100)
+}
+
+// compatible reports whether a client can dump or restore a server of the given version
+func compatible(client, server int) bool {
+	if majorNumber(client) < majorNumber(server) {
+		return false
+	}
+	// pg_dump 15 and newer dropped support for servers older than 9.2
+	if client >= 150000 && server < 90200 {
+		return false
+	}
+	return true
+}
+
+// majorNumber returns a comparable major version: 16 → 1600, 9.6 → 906
+func majorNumber(version int) int {
+	if version >= 100000 {
+		return version / 10000 * 100
+	}
+	return version / 100
+}
@@ -0,0 +1,96 @@
+package backup
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/database"
+)
+
+// containerRuntimes lists the container CLIs tried, in order, when no runtime is configured
+var containerRuntimes = []string{config.RuntimeDocker, config.RuntimePodman}
+
+// containerClient runs tool inside a postgres image tagged with the server's major version
+func (s *Service) containerClient(tool, name string, settings config.PgDumpSettings, serverVersion int) (ClientBinary, error) {
+	candidates := containerRuntimes
+	if name != "" {
+		candidates = []string{name}
+	}
+
+	for _, candidate := range candidates {
+		path, err := exec.LookPath(candidate)
+		if err != nil {
+			continue
+		}
+
+		image := settings.ImageOrDefault() + ":latest"
+		if serverVersion > 0 {
+			image = settings.ImageOrDefault() + ":" + MajorVersion(serverVersion)
+		}
+
+		s.logger.Info("selected container client", "tool", tool, "runtime", path, "image", image)
+		return ClientBinary{Tool: tool, Path: tool, Version: serverVersion, Runtime: path, Image: image}, nil
+	}
+	return ClientBinary{}, fmt.Errorf("no container runtime found in PATH (tried %s)", strings.Join(candidates, ", "))
+}
+
+// Containerized reports whether the client runs inside a container
+func (c ClientBinary) Containerized() bool {
+	return c.Runtime != ""
+}
+
+// Command builds the command running the client with args. Containerized clients receive
+// the libpq variables of database.ClientEnv from the host environment and use stdin/stdout for archive data.
+func (c ClientBinary) Command(args ...string) *exec.Cmd {
+	return c.CommandMounting(nil, args...)
+}
+
+// CommandMounting is like Command, but a containerized client also sees each host directory
+// in dirs, mounted read-only at the same path, so file arguments can be passed unchanged
+func (c ClientBinary) CommandMounting(dirs []string, args ...string) *exec.Cmd {
+	if !c.Containerized() {
+		return exec.Command(c.Path, args...)
+	}
+
+	runArgs := []string{"run", "--rm", "-i"}
+	for _, name := range database.ClientEnvVars {
+		runArgs = append(runArgs, "-e", name)
+	}
+	if runtime.GOOS == "linux" {
+		runArgs = append(runArgs, "--network", "host")
+	}
+	for _, dir := range dirs {
+		runArgs = append(runArgs, "-v", dir+":"+dir+":ro")
+	}
+	runArgs = append(runArgs, c.Image, c.Tool)
+	return exec.Command(c.Runtime, append(runArgs, args...)...)
+}
+
+// DialHost returns the host the client must dial to reach host. Outside Linux, containers
+// cannot share the host network, so loopback addresses (including SSH tunnels) go
+// through the runtime's gateway name instead.
+func (c ClientBinary) DialHost(host string) string {
+	if !c.Containerized() || runtime.GOOS == "linux" {
+		return host
+	}
+	switch host {
+	case "localhost", "127.0.0.1", "::1":
+		if strings.Contains(filepath.Base(c.Runtime), config.RuntimePodman) {
+			return "host.containers.internal"
+		}
+		return "host.docker.internal"
+	}
+	return host
+}
+
+// String describes the client for display
+func (c ClientBinary) String() string {
+	if c.Containerized() {
+		return fmt.Sprintf("%s (%s, %s)", c.Tool, filepath.Base(c.Runtime), c.Image)
+	}
+	return fmt.Sprintf("%s (%s)", c.Path, FormatVersion(c.Version))
+}
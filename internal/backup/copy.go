@@ -0,0 +1,171 @@
+package backup
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/database"
+	"github.com/Luiz-F3lipe/snapTUI/internal/logging"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// copyProgressInterval is how often a running copy reports the bytes streamed
+const copyProgressInterval = 250 * time.Millisecond
+
+// CopyClients returns the pg_dump that reads the source and the program that loads its
+// output into the target: psql for plain-format profiles, pg_restore otherwise
+func (s *Service) CopyClients(source types.DatabaseConnection, sourceDump config.PgDumpSettings,
+	target types.DatabaseConnection, targetDump config.PgDumpSettings) (ClientBinary, ClientBinary, error) {
+	pgDump, err := s.FindPgDump(sourceDump, source.ServerVersion)
+	if err != nil {
+		return ClientBinary{}, ClientBinary{}, err
+	}
+
+	// The loader must handle the target server and read what this pg_dump writes
+	version := max(target.ServerVersion, pgDump.Version)
+	var loader ClientBinary
+	if sourceDump.FormatOrDefault() == config.DumpFormatPlain {
+		loader, err = s.FindPsql(targetDump, version)
+	} else {
+		loader, err = s.FindPgRestore(targetDump, version)
+	}
+	return pgDump, loader, err
+}
+
+// CopyDatabase streams pg_dump of dbname on source straight into targetDB on target, which
+// must already exist, without an intermediate file. progress receives the bytes streamed
+// so far while the copy runs. It returns the messages printed by the loader.
+func (s *Service) CopyDatabase(source types.DatabaseConnection, dbname string, sourceDump config.PgDumpSettings,
+	target types.DatabaseConnection, targetDB string, targetDump config.PgDumpSettings, progress func(int64)) (string, error) {
+	pgDump, loader, err := s.CopyClients(source, sourceDump, target, targetDump)
+	if err != nil {
+		return "", err
+	}
+
+	// Custom format is streamed unless the profile dumps plain SQL, which goes through psql
+	format := config.DumpFormatCustom
+	if sourceDump.FormatOrDefault() == config.DumpFormatPlain {
+		format = config.DumpFormatPlain
+	}
+	host, port := source.Address()
+	dumpArgs := []string{
+		"--host", pgDump.DialHost(host),
+		"--port", port,
+		"--username", source.User,
+		"--no-password",
+		"--format", format,
+	}
+	dumpArgs = append(dumpArgs, sourceDump.Args...)
+	dumpCmd := pgDump.Command(append(dumpArgs, dbname)...)
+	dumpCmd.Env = database.ClientEnv(source)
+
+	host, port = target.Address()
+	loadArgs := []string{
+		"--host", loader.DialHost(host),
+		"--port", port,
+		"--username", target.User,
+		"--no-password",
+		"--dbname", targetDB,
+	}
+	if format == config.DumpFormatPlain {
+		loadArgs = append(loadArgs, "--quiet", "--no-psqlrc", "--set", "ON_ERROR_STOP=1")
+	} else {
+		// Roles and grants of the source server rarely exist on the target
+		loadArgs = append(loadArgs, "--no-owner", "--no-acl")
+	}
+	loadCmd := loader.Command(loadArgs...)
+	loadCmd.Env = database.ClientEnv(target)
+
+	pipeReader, pipeWriter := io.Pipe()
+	counter := &countingWriter{w: pipeWriter}
+	var dumpOutput, loadOutput bytes.Buffer
+	dumpCmd.Stdout, dumpCmd.Stderr = counter, &dumpOutput
+	loadCmd.Stdin = pipeReader
+	loadCmd.Stdout, loadCmd.Stderr = io.Discard, &loadOutput
+	if format != config.DumpFormatPlain {
+		loadCmd.Stdout = &loadOutput
+	}
+
+	logger := s.logger.With("source_host", source.Host, "database", dbname, "target_host", target.Host, "target_database", targetDB)
+	logger.Info("starting database copy",
+		"dump_command", logging.RedactArgs(dumpCmd.Path, dumpCmd.Args[1:]),
+		"load_command", logging.RedactArgs(loadCmd.Path, loadCmd.Args[1:]))
+	start := time.Now()
+
+	if err := loadCmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to start %s: %w", loader.Tool, err)
+	}
+	if err := dumpCmd.Start(); err != nil {
+		pipeWriter.Close()
+		loadCmd.Wait()
+		return "", fmt.Errorf("failed to start pg_dump: %w", err)
+	}
+
+	done, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(copyProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				progress(counter.n.Load())
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	// A loader that exits early closes the pipe so pg_dump fails instead of blocking,
+	// and pg_dump closes it with its own result so the loader sees the end of its input
+	loadDone := make(chan error, 1)
+	go func() {
+		err := loadCmd.Wait()
+		pipeReader.CloseWithError(fmt.Errorf("%s exited", loader.Tool))
+		loadDone <- err
+	}()
+	dumpErr := dumpCmd.Wait()
+	pipeWriter.CloseWithError(dumpErr)
+	loadErr := <-loadDone
+	close(done)
+	<-stopped
+	progress(counter.n.Load())
+
+	if dumpErr != nil || loadErr != nil {
+		// When one side fails the other usually fails too, so both outputs are reported
+		output := strings.TrimSpace(dumpOutput.String() + "\n" + loadOutput.String())
+		logger.Error("database copy failed", "duration", time.Since(start), "dump_error", dumpErr, "load_error", loadErr,
+			"stderr", logging.Redact(output))
+		switch {
+		case dumpErr == nil:
+			return "", fmt.Errorf("failed to execute %s into %s: %w\nOutput: %s", loader.Tool, targetDB, loadErr, output)
+		case loadErr == nil:
+			return "", fmt.Errorf("failed to execute pg_dump for %s: %w\nOutput: %s", dbname, dumpErr, output)
+		}
+		return "", fmt.Errorf("failed to copy %s into %s: pg_dump: %v, %s: %v\nOutput: %s", dbname, targetDB, dumpErr, loader.Tool, loadErr, output)
+	}
+
+	output := strings.TrimSpace(dumpOutput.String() + loadOutput.String())
+	if output != "" {
+		logger.Warn("database copy reported messages", "stderr", logging.Redact(output))
+	}
+	logger.Info("database copy finished", "duration", time.Since(start), "bytes", counter.n.Load())
+	return output, nil
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n atomic.Int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package backup
+
+import "fmt"
+
+// freeSpace is not implemented on Windows; the pre-flight check reports it as unknown
+func freeSpace(path string) (int64, error) {
+	return -1, fmt.Errorf("free space check not supported on windows")
+}
@@ -0,0 +1,126 @@
+package backup
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/catalog"
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PerformMultiBackupCmd creates a command that backs up databases from several servers at once,
+// running at most perHost dumps concurrently against each server
+func (s *Service) PerformMultiBackupCmd(targets []types.BackupTarget, perHost int) tea.Cmd {
+	return func() tea.Msg {
+		startedAt := time.Now()
+		if perHost < 1 {
+			perHost = 1
+		}
+
+		// Results keep the order in which profiles were selected
+		var mu sync.Mutex
+		results := make(map[string]*types.HostResult)
+		var order []string
+		for _, t := range targets {
+			if _, ok := results[t.Profile]; !ok {
+				results[t.Profile] = &types.HostResult{Profile: t.Profile, Host: t.Connection.Host}
+				order = append(order, t.Profile)
+			}
+		}
+
+		baseDir, err := s.BackupDir()
+		if err != nil {
+			for _, r := range results {
+				r.Errors = append(r.Errors, err.Error())
+			}
+			return s.multiComplete(order, results, startedAt)
+		}
+
+		limits := make(map[string]chan struct{})
+		var wg sync.WaitGroup
+		for _, t := range targets {
+			server := net.JoinHostPort(t.Connection.Host, t.Connection.Port)
+			if _, ok := limits[server]; !ok {
+				limits[server] = make(chan struct{}, perHost)
+			}
+			limit := limits[server]
+
+			wg.Add(1)
+			go func(t types.BackupTarget) {
+				defer wg.Done()
+				limit <- struct{}{}
+				defer func() { <-limit }()
+
+				entry := catalog.Entry{
+					Profile:    t.Profile,
+					Host:       t.Connection.Host,
+					Port:       t.Connection.Port,
+					Database:   t.Database,
+					Kind:       catalog.KindDump,
+					SourceSize: t.SourceSize,
+					StartedAt:  time.Now(),
+				}
+
+				dir := filepath.Join(baseDir, hostDir(t.Profile, t.Connection))
+				path, err := "", os.MkdirAll(dir, 0o755)
+				if err == nil {
+					path, err = s.dumpDatabase(dir, t.Connection, t.Database, "", t.PgDump)
+				}
+
+				mu.Lock()
+				r := results[t.Profile]
+				if err != nil {
+					r.Errors = append(r.Errors, fmt.Sprintf("Error backing up %s: %v", t.Database, err))
+					entry.Error = err.Error()
+				} else {
+					r.Success++
+					r.Filenames = append(r.Filenames, filepath.Join(filepath.Base(dir), filepath.Base(path)))
+					entry.Success = true
+					entry.Path = path
+				}
+				mu.Unlock()
+
+				s.record(entry)
+			}(t)
+		}
+		wg.Wait()
+
+		return s.multiComplete(order, results, startedAt)
+	}
+}
+
+// multiComplete builds the completion message of a multi-server run
+func (s *Service) multiComplete(order []string, results map[string]*types.HostResult, startedAt time.Time) types.MultiBackupCompleteMsg {
+	msg := types.MultiBackupCompleteMsg{StartedAt: startedAt, FinishedAt: time.Now()}
+	for _, profile := range order {
+		r := results[profile]
+		msg.Results = append(msg.Results, *r)
+		s.logger.Info("multi-server backup finished for host", "profile", r.Profile, "host", r.Host,
+			"success", r.Success, "errors", len(r.Errors))
+	}
+	s.logger.Info("multi-server backup run finished", "hosts", len(order), "duration", msg.FinishedAt.Sub(startedAt))
+	return msg
+}
+
+// hostDir returns the directory name grouping the backups a profile takes of its server. The
+// profile is part of the name: two profiles reaching the same server would otherwise write the
+// same file names in the same second.
+func hostDir(profile string, conn types.DatabaseConnection) string {
+	name := profile + "_" + conn.Host
+	if conn.Port != "" && conn.Port != config.DefaultPort {
+		name += "_" + conn.Port
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' {
+			return '_'
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,213 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/catalog"
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/database"
+	"github.com/Luiz-F3lipe/snapTUI/internal/logging"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// basebackupProgress matches the progress lines of pg_basebackup --progress, e.g. "1024/20480 kB (5%)"
+var basebackupProgress = regexp.MustCompile(`^\s*(\d+)/(\d+) kB \(\d+%\)`)
+
+// unsafeName matches characters replaced when a host or schema name becomes part of a file name
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// PhysicalBackup runs pg_basebackup for the whole cluster of conn into a new directory under dir:
+// tar format, gzip-compressed, with the WAL written during the backup streamed alongside so it
+// is consistent on its own. Progress is reported in kB. It returns the backup directory and the
+// errors and warnings printed by pg_basebackup.
+func (s *Service) PhysicalBackup(conn types.DatabaseConnection, settings config.PgDumpSettings, dir string,
+	progress func(types.PhysicalProgressMsg)) (string, string, error) {
+	pgBasebackup, err := s.PhysicalClient(settings, conn.ServerVersion)
+	if err != nil {
+		return "", "", err
+	}
+
+	timestamp := time.Now().Format("20060102_150405")
+	target := filepath.Join(dir, fmt.Sprintf("basebackup_%s_%s", unsafeName.ReplaceAllString(conn.Host, "_"), timestamp))
+
+	host, port := conn.Address()
+	cmd := pgBasebackup.Command(
+		"--host", pgBasebackup.DialHost(host),
+		"--port", port,
+		"--username", conn.User,
+		"--no-password",
+		"--pgdata", target,
+		"--format", "tar",
+		"--gzip",
+		"--wal-method", "stream",
+		"--checkpoint", "fast",
+		"--label", "snapTUI "+timestamp,
+		"--progress",
+		"--verbose",
+	)
+	cmd.Env = database.ClientEnv(conn)
+
+	pipe, err := cmd.StderrPipe()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read pg_basebackup output: %w", err)
+	}
+	cmd.Stdout = cmd.Stderr
+
+	logger := s.logger.With("host", conn.Host)
+	logger.Info("starting pg_basebackup", "command", logging.RedactArgs(cmd.Path, cmd.Args[1:]), "dir", target)
+	start := time.Now()
+
+	if err := cmd.Start(); err != nil {
+		return "", "", fmt.Errorf("failed to start pg_basebackup: %w", err)
+	}
+
+	// Progress lines end in a carriage return when stderr is a terminal and in a newline otherwise
+	var all, messages bytes.Buffer
+	scanner := bufio.NewScanner(pipe)
+	scanner.Split(scanLinesOrReturns)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := basebackupProgress.FindStringSubmatch(line); match != nil {
+			done, _ := strconv.ParseInt(match[1], 10, 64)
+			total, _ := strconv.ParseInt(match[2], 10, 64)
+			progress(types.PhysicalProgressMsg{DoneKB: done, TotalKB: total})
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		all.WriteString(line + "\n")
+		lower := strings.ToLower(line)
+		if strings.Contains(lower, "error") || strings.Contains(lower, "warning") || strings.Contains(lower, "fatal") {
+			messages.WriteString(line + "\n")
+		}
+	}
+	// Drain whatever the scanner could not read so pg_basebackup never blocks on a full pipe
+	io.Copy(io.Discard, pipe)
+
+	if err := cmd.Wait(); err != nil {
+		os.RemoveAll(target)
+		output := strings.TrimSpace(all.String())
+		logger.Error("pg_basebackup failed", "duration", time.Since(start), "error", err, "stderr", logging.Redact(output))
+		return "", "", fmt.Errorf("failed to execute pg_basebackup: %w\nOutput: %s", err, output)
+	}
+
+	output := strings.TrimSpace(messages.String())
+	if output != "" {
+		logger.Warn("pg_basebackup reported messages", "stderr", logging.Redact(output))
+	}
+	logger.Info("pg_basebackup finished", "duration", time.Since(start), "dir", target)
+	return target, output, nil
+}
+
+// PhysicalClient returns the pg_basebackup used for physical backups. It must run locally:
+// tar output with streamed WAL needs a writable target directory, which containers do not get.
+func (s *Service) PhysicalClient(settings config.PgDumpSettings, serverVersion int) (ClientBinary, error) {
+	pgBasebackup, err := s.FindPgBasebackup(settings, serverVersion)
+	if err != nil {
+		return ClientBinary{}, err
+	}
+	if pgBasebackup.Containerized() {
+		return ClientBinary{}, fmt.Errorf("physical backups need a local pg_basebackup %s or newer; containerized clients are not supported",
+			MajorVersion(serverVersion))
+	}
+	return pgBasebackup, nil
+}
+
+// scanLinesOrReturns is a bufio.SplitFunc that ends tokens at either '\n' or '\r'
+func scanLinesOrReturns(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// dirSize returns the total size of the regular files under dir
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// StartPhysicalBackup runs a base backup of conn's cluster in the background, records it in the
+// catalog and applies the profile's retention. The returned channel delivers PhysicalProgressMsg
+// updates followed by a final PhysicalCompleteMsg, then is closed.
+func (s *Service) StartPhysicalBackup(conn types.DatabaseConnection, profile string, settings config.PgDumpSettings,
+	physical config.PhysicalSettings) <-chan tea.Msg {
+	updates := make(chan tea.Msg, 1)
+	go func() {
+		defer close(updates)
+		msg := types.PhysicalCompleteMsg{StartedAt: time.Now()}
+
+		// Keep only the latest progress so a slow UI never stalls pg_basebackup's output
+		progress := func(p types.PhysicalProgressMsg) {
+			select {
+			case <-updates:
+			default:
+			}
+			updates <- p
+		}
+
+		dir, err := s.BackupDir()
+		if err == nil {
+			msg.Path, msg.Warnings, err = s.PhysicalBackup(conn, settings, dir, progress)
+		}
+		msg.Err = err
+
+		entry := catalog.Entry{
+			Profile:    profile,
+			Host:       conn.Host,
+			Port:       conn.Port,
+			Kind:       catalog.KindBase,
+			Path:       msg.Path,
+			StartedAt:  msg.StartedAt,
+			FinishedAt: time.Now(),
+			Success:    err == nil,
+		}
+		if err != nil {
+			entry.Error = err.Error()
+		} else {
+			msg.Size = dirSize(msg.Path)
+			entry.OutputSize = msg.Size
+		}
+		if recordErr := s.catalog.Record(entry); recordErr != nil {
+			s.logger.Error("failed to record base backup in catalog", "error", recordErr)
+		}
+
+		if err == nil {
+			msg.Removed, msg.RemovedWAL, err = s.applyRetention(conn, physical)
+			if err != nil {
+				msg.Warnings = strings.TrimSpace(msg.Warnings + "\n" + err.Error())
+			}
+		}
+
+		select {
+		case <-updates:
+		default:
+		}
+		msg.FinishedAt = time.Now()
+		updates <- msg
+	}()
+	return updates
+}
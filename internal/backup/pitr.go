@@ -0,0 +1,419 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// lsnPattern matches a WAL location as printed by PostgreSQL, e.g. 0/3000148
+var lsnPattern = regexp.MustCompile(`^[0-9A-Fa-f]{1,8}/[0-9A-Fa-f]{1,8}$`)
+
+// recoveryTimeLayouts are the accepted forms of a recovery target time; those without a zone
+// are read in the local time zone
+var recoveryTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// RecoveryTarget is where a point-in-time recovery stops: a time, a WAL location, or neither
+// to replay every archived segment
+type RecoveryTarget struct {
+	Time time.Time
+	LSN  string
+}
+
+// IsZero reports whether the recovery replays all archived WAL
+func (t RecoveryTarget) IsZero() bool {
+	return t.Time.IsZero() && t.LSN == ""
+}
+
+// Setting returns the recovery_target_* line for postgresql.auto.conf, or "" for no target
+func (t RecoveryTarget) Setting() string {
+	switch {
+	case t.LSN != "":
+		return fmt.Sprintf("recovery_target_lsn = '%s'", t.LSN)
+	case !t.Time.IsZero():
+		return fmt.Sprintf("recovery_target_time = '%s'", t.Time.Format("2006-01-02 15:04:05-07:00"))
+	}
+	return ""
+}
+
+// ParseRecoveryTarget reads a recovery target typed by the user: an LSN such as 0/3000148, a
+// time such as "2024-05-10 14:30[:00]" with an optional zone offset, or "" for no target
+func ParseRecoveryTarget(value string) (RecoveryTarget, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return RecoveryTarget{}, nil
+	}
+	if lsnPattern.MatchString(value) {
+		return RecoveryTarget{LSN: strings.ToUpper(value)}, nil
+	}
+	for _, layout := range recoveryTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return RecoveryTarget{Time: t}, nil
+		}
+	}
+	return RecoveryTarget{}, fmt.Errorf("invalid recovery target %q: use YYYY-MM-DD HH:MM[:SS] or an LSN like 0/3000148", value)
+}
+
+// parseLSN converts an LSN to its 64-bit position
+func parseLSN(lsn string) (uint64, error) {
+	hi, lo, ok := strings.Cut(lsn, "/")
+	if !ok {
+		return 0, fmt.Errorf("invalid LSN %q", lsn)
+	}
+	h, err := strconv.ParseUint(hi, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid LSN %q", lsn)
+	}
+	l, err := strconv.ParseUint(lo, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid LSN %q", lsn)
+	}
+	return h<<32 | l, nil
+}
+
+// BaseBackups returns the base backups of the catalog that can still be recovered, newest first
+func (s *Service) BaseBackups() ([]types.BaseBackup, error) {
+	entries, err := s.baseBackupEntries()
+	if err != nil {
+		return nil, err
+	}
+	backups := make([]types.BaseBackup, 0, len(entries))
+	for _, e := range entries {
+		backups = append(backups, types.BaseBackup{
+			Profile:    e.Profile,
+			Host:       e.Host,
+			Port:       e.Port,
+			Path:       e.Path,
+			StartedAt:  e.StartedAt,
+			FinishedAt: e.FinishedAt,
+		})
+	}
+	return backups, nil
+}
+
+// PITRDataDir suggests the data directory a base backup is recovered into, next to the backup
+func PITRDataDir(backupDir string) string {
+	name := strings.TrimPrefix(filepath.Base(backupDir), "basebackup_")
+	return filepath.Join(filepath.Dir(backupDir), "pitr_"+name)
+}
+
+// PreparePITR unpacks a base backup into dataDir and configures it to recover up to target
+// with the WAL archived in walArchiveDir: the recovery settings go to postgresql.auto.conf and
+// recovery.signal puts the server in targeted recovery on its next start. Archiving is turned
+// off so the recovered server never writes into the archive it reads from. The server itself
+// is not started.
+func (s *Service) PreparePITR(backup types.BaseBackup, dataDir, walArchiveDir string, target RecoveryTarget) (types.PITRCompleteMsg, error) {
+	msg := types.PITRCompleteMsg{Backup: backup.Path, Target: target.Setting()}
+
+	label, err := ReadBackupLabel(backup.Path)
+	if err != nil {
+		return msg, err
+	}
+	msg.StartWAL = label.StartWAL
+	if err := checkRecoveryTarget(backup, label, target); err != nil {
+		return msg, err
+	}
+
+	if msg.WALArchiveDir, err = filepath.Abs(walArchiveDir); err != nil {
+		return msg, err
+	}
+	if info, err := os.Stat(msg.WALArchiveDir); err != nil || !info.IsDir() {
+		return msg, fmt.Errorf("WAL archive %s is not a directory", msg.WALArchiveDir)
+	}
+	restoreCmd, err := restoreCommand(msg.WALArchiveDir)
+	if err != nil {
+		return msg, err
+	}
+	if msg.ArchivedWAL, err = countArchivedWAL(msg.WALArchiveDir, label.StartWAL); err != nil {
+		return msg, err
+	}
+
+	// Tablespaces come as <oid>.tar.gz and must go back to their original paths
+	archives, _ := filepath.Glob(filepath.Join(backup.Path, "*.tar.gz"))
+	for _, archive := range archives {
+		if name := filepath.Base(archive); name != "base.tar.gz" && name != "pg_wal.tar.gz" {
+			return msg, fmt.Errorf("base backups with tablespaces are not supported: %s must be extracted by hand", name)
+		}
+	}
+
+	if msg.DataDir, err = filepath.Abs(dataDir); err != nil {
+		return msg, err
+	}
+	created, err := createDataDir(msg.DataDir)
+	if err != nil {
+		return msg, err
+	}
+
+	logger := s.logger.With("backup", backup.Path, "data_dir", msg.DataDir)
+	logger.Info("preparing point-in-time recovery", "target", msg.Target, "start_wal", label.StartWAL)
+
+	err = extractBaseBackup(backup.Path, msg.DataDir)
+	if err == nil {
+		// The version decides how recovery is configured, so it is checked before writing anything
+		msg.Version, err = recoveryVersion(msg.DataDir)
+	}
+	if err == nil {
+		err = writeRecoverySettings(msg.DataDir, filepath.Base(backup.Path), restoreCmd, target)
+	}
+	if err != nil {
+		cleanDataDir(msg.DataDir, created)
+		logger.Error("failed to prepare point-in-time recovery", "error", err)
+		return msg, err
+	}
+
+	logger.Info("point-in-time recovery prepared", "version", msg.Version, "archived_wal", msg.ArchivedWAL)
+	return msg, nil
+}
+
+// PreparePITRCmd creates a command that prepares a point-in-time recovery
+func (s *Service) PreparePITRCmd(backup types.BaseBackup, dataDir, walArchiveDir string, target RecoveryTarget) tea.Cmd {
+	return func() tea.Msg {
+		msg, err := s.PreparePITR(backup, dataDir, walArchiveDir, target)
+		msg.Err = err
+		return msg
+	}
+}
+
+// checkRecoveryTarget rejects targets that precede the end of the base backup, which PostgreSQL
+// could only report after replaying the whole backup: recovery cannot stop before the copy is
+// consistent, and it is only consistent once the backup has finished
+func checkRecoveryTarget(backup types.BaseBackup, label BaseBackupLabel, target RecoveryTarget) error {
+	finished := backup.FinishedAt
+	if finished.IsZero() {
+		finished = backup.StartedAt
+	}
+	if !target.Time.IsZero() && !finished.IsZero() && target.Time.Before(finished) {
+		return fmt.Errorf("recovery target %s is before the base backup finished (%s)",
+			target.Time.Format("2006-01-02 15:04:05"), finished.Local().Format("2006-01-02 15:04:05"))
+	}
+	if target.LSN != "" {
+		want, err := parseLSN(target.LSN)
+		if err != nil {
+			return err
+		}
+		start, err := parseLSN(label.StartLSN)
+		if err == nil && want < start {
+			return fmt.Errorf("recovery target %s is before the base backup start %s", target.LSN, label.StartLSN)
+		}
+	}
+	return nil
+}
+
+// restoreCommand returns the restore_command copying segments out of the WAL archive. Paths
+// with characters that need escaping in postgresql.auto.conf or the shell are rejected.
+func restoreCommand(walArchiveDir string) (string, error) {
+	forbidden := "\"'%\n"
+	copyCmd := "cp"
+	if runtime.GOOS == "windows" {
+		copyCmd = "copy"
+	} else {
+		forbidden += "$`\\"
+	}
+	if strings.ContainsAny(walArchiveDir, forbidden) {
+		return "", fmt.Errorf("WAL archive path %q has characters not allowed in restore_command", walArchiveDir)
+	}
+	return fmt.Sprintf(`%s "%s" "%%p"`, copyCmd, filepath.Join(walArchiveDir, "%f")), nil
+}
+
+// countArchivedWAL counts the WAL segments in dir from startWAL on, whatever their timeline
+func countArchivedWAL(dir, startWAL string) (int, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read WAL archive: %w", err)
+	}
+	count := 0
+	for _, f := range files {
+		name := f.Name()
+		if f.Type().IsRegular() && walFileName.MatchString(name) && len(name) == 24 && name[8:] >= startWAL[8:] {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// createDataDir creates dataDir with the permissions PostgreSQL requires, accepting an existing
+// directory only when it is empty. It reports whether the directory was created.
+func createDataDir(dataDir string) (bool, error) {
+	entries, err := os.ReadDir(dataDir)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if err := os.MkdirAll(dataDir, 0o700); err != nil {
+			return false, fmt.Errorf("failed to create data directory: %w", err)
+		}
+		return true, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to read data directory: %w", err)
+	case len(entries) > 0:
+		return false, fmt.Errorf("data directory %s is not empty", dataDir)
+	}
+	return false, os.Chmod(dataDir, 0o700)
+}
+
+// cleanDataDir undoes a failed recovery setup, keeping a directory that existed before
+func cleanDataDir(dataDir string, created bool) {
+	if created {
+		os.RemoveAll(dataDir)
+		return
+	}
+	entries, _ := os.ReadDir(dataDir)
+	for _, e := range entries {
+		os.RemoveAll(filepath.Join(dataDir, e.Name()))
+	}
+}
+
+// extractBaseBackup unpacks the base backup and its streamed WAL into dataDir
+func extractBaseBackup(backupDir, dataDir string) error {
+	if err := extractTarGz(filepath.Join(backupDir, "base.tar.gz"), dataDir); err != nil {
+		return err
+	}
+	walArchive := filepath.Join(backupDir, "pg_wal.tar.gz")
+	if _, err := os.Stat(walArchive); err == nil {
+		if err := extractTarGz(walArchive, filepath.Join(dataDir, "pg_wal")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recoveryVersion reads PG_VERSION from an extracted data directory. recovery.signal and the
+// recovery settings in postgresql.auto.conf only exist from PostgreSQL 12 on; older servers
+// use recovery.conf, which is not written.
+func recoveryVersion(dataDir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, "PG_VERSION"))
+	if err != nil {
+		return "", fmt.Errorf("base backup has no PG_VERSION: %w", err)
+	}
+	version := strings.TrimSpace(string(data))
+	major, _, _ := strings.Cut(version, ".")
+	n, err := strconv.Atoi(major)
+	if err != nil {
+		return "", fmt.Errorf("base backup has an invalid PG_VERSION %q", version)
+	}
+	if n < 12 {
+		return version, fmt.Errorf("base backup is from PostgreSQL %s: point-in-time recovery needs PostgreSQL 12 or later (older versions use recovery.conf)", version)
+	}
+	return version, nil
+}
+
+// writeRecoverySettings appends the recovery settings to postgresql.auto.conf and writes
+// recovery.signal
+func writeRecoverySettings(dataDir, backupName, restoreCmd string, target RecoveryTarget) error {
+	settings := []string{
+		"",
+		"# Point-in-time recovery from " + backupName,
+		fmt.Sprintf("restore_command = '%s'", restoreCmd),
+		"archive_mode = 'off'",
+	}
+	if !target.IsZero() {
+		// Pause at the target so the data can be checked before the server is promoted
+		settings = append(settings, target.Setting(), "recovery_target_action = 'pause'")
+	}
+	conf, err := os.OpenFile(filepath.Join(dataDir, "postgresql.auto.conf"), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write postgresql.auto.conf: %w", err)
+	}
+	if _, err := conf.WriteString(strings.Join(settings, "\n") + "\n"); err != nil {
+		conf.Close()
+		return fmt.Errorf("failed to write postgresql.auto.conf: %w", err)
+	}
+	if err := conf.Close(); err != nil {
+		return fmt.Errorf("failed to write postgresql.auto.conf: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dataDir, "recovery.signal"), nil, 0o600); err != nil {
+		return fmt.Errorf("failed to write recovery.signal: %w", err)
+	}
+	return nil
+}
+
+// extractTarGz unpacks a gzip-compressed tar archive made by pg_basebackup into dir. The only
+// symbolic links pg_basebackup writes are the tablespace links in pg_tblspc; others are skipped,
+// and no entry may be written through a link, which could point anywhere on the host.
+func extractTarGz(archive, dir string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(archive), err)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(gz)
+	links := make(map[string]bool) // symbolic links created so far, relative to dir
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", filepath.Base(archive), err)
+		}
+		if !filepath.IsLocal(header.Name) {
+			return fmt.Errorf("%s: unsafe path %q", filepath.Base(archive), header.Name)
+		}
+		name := filepath.Clean(header.Name)
+		for parent := filepath.Dir(name); parent != "."; parent = filepath.Dir(parent) {
+			if links[parent] {
+				return fmt.Errorf("%s: unsafe path %q goes through the symbolic link %s", filepath.Base(archive), header.Name, parent)
+			}
+		}
+		path := filepath.Join(dir, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(path, 0o700)
+		case tar.TypeReg:
+			err = extractFile(tr, path, header.FileInfo().Mode().Perm())
+		case tar.TypeSymlink:
+			if filepath.Dir(name) != "pg_tblspc" {
+				continue
+			}
+			if err = os.MkdirAll(filepath.Dir(path), 0o700); err == nil {
+				err = os.Symlink(header.Linkname, path)
+			}
+			links[name] = true
+		}
+		if err != nil {
+			return fmt.Errorf("failed to extract %s: %w", header.Name, err)
+		}
+	}
+}
+
+// extractFile writes the current tar entry to path
+func extractFile(r io.Reader, path string, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,282 @@
+package backup
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+func TestParseRecoveryTarget(t *testing.T) {
+	tests := []struct {
+		value string
+		want  RecoveryTarget
+		err   bool
+	}{
+		{value: "", want: RecoveryTarget{}},
+		{value: "   ", want: RecoveryTarget{}},
+		{value: "0/3000148", want: RecoveryTarget{LSN: "0/3000148"}},
+		{value: " 16/b374d848 ", want: RecoveryTarget{LSN: "16/B374D848"}},
+		{value: "2024-05-10 14:30", want: RecoveryTarget{Time: time.Date(2024, 5, 10, 14, 30, 0, 0, time.Local)}},
+		{value: "2024-05-10 14:30:15", want: RecoveryTarget{Time: time.Date(2024, 5, 10, 14, 30, 15, 0, time.Local)}},
+		{value: "2024-05-10T14:30:15Z", want: RecoveryTarget{Time: time.Date(2024, 5, 10, 14, 30, 15, 0, time.UTC)}},
+		{value: "2024-05-10 14:30:15-03:00", want: RecoveryTarget{Time: time.Date(2024, 5, 10, 17, 30, 15, 0, time.UTC)}},
+		{value: "2024-05-10 14:30:15 +02:00", want: RecoveryTarget{Time: time.Date(2024, 5, 10, 12, 30, 15, 0, time.UTC)}},
+		{value: "yesterday", err: true},
+		{value: "2024-05-10", err: true},
+		{value: "2024-13-10 14:30", err: true},
+		{value: "0/XYZ", err: true},
+		{value: "123456789/0", err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRecoveryTarget(tt.value)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseRecoveryTarget(%q) = %+v, want an error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRecoveryTarget(%q): %v", tt.value, err)
+			continue
+		}
+		if got.LSN != tt.want.LSN || !got.Time.Equal(tt.want.Time) {
+			t.Errorf("ParseRecoveryTarget(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRecoveryTargetSetting(t *testing.T) {
+	zone := time.FixedZone("-03", -3*60*60)
+	tests := []struct {
+		target RecoveryTarget
+		want   string
+	}{
+		{RecoveryTarget{}, ""},
+		{RecoveryTarget{LSN: "0/3000148"}, "recovery_target_lsn = '0/3000148'"},
+		{RecoveryTarget{Time: time.Date(2024, 5, 10, 14, 30, 0, 0, zone)}, "recovery_target_time = '2024-05-10 14:30:00-03:00'"},
+	}
+	for _, tt := range tests {
+		if got := tt.target.Setting(); got != tt.want {
+			t.Errorf("Setting() = %q, want %q", got, tt.want)
+		}
+		if tt.target.IsZero() != (tt.want == "") {
+			t.Errorf("IsZero() = %v for %+v", tt.target.IsZero(), tt.target)
+		}
+	}
+}
+
+func TestParseLSN(t *testing.T) {
+	tests := []struct {
+		lsn  string
+		want uint64
+		err  bool
+	}{
+		{lsn: "0/0", want: 0},
+		{lsn: "0/3000148", want: 0x3000148},
+		{lsn: "16/B374D848", want: 0x16<<32 | 0xB374D848},
+		{lsn: "16/b374d848", want: 0x16<<32 | 0xB374D848},
+		{lsn: "FFFFFFFF/FFFFFFFF", want: 1<<64 - 1},
+		{lsn: "3000148", err: true},
+		{lsn: "G/1", err: true},
+		{lsn: "1/100000000", err: true},
+		{lsn: "100000000/1", err: true},
+		{lsn: "", err: true},
+	}
+	for _, tt := range tests {
+		got, err := parseLSN(tt.lsn)
+		if tt.err {
+			if err == nil {
+				t.Errorf("parseLSN(%q) = %d, want an error", tt.lsn, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseLSN(%q) = %d, %v; want %d", tt.lsn, got, err, tt.want)
+		}
+	}
+
+	// Positions compare in WAL order, not as strings
+	a, _ := parseLSN("0/FFFFFFFF")
+	b, _ := parseLSN("1/0")
+	if a >= b {
+		t.Errorf("0/FFFFFFFF (%d) is not before 1/0 (%d)", a, b)
+	}
+}
+
+func TestRestoreCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("restore_command uses cp and POSIX paths")
+	}
+	tests := []struct {
+		dir  string
+		want string
+		err  bool
+	}{
+		{dir: "/var/lib/wal", want: `cp "/var/lib/wal/%f" "%p"`},
+		{dir: "/mnt/wal archive", want: `cp "/mnt/wal archive/%f" "%p"`},
+		{dir: "/mnt/arquivo-ção", want: `cp "/mnt/arquivo-ção/%f" "%p"`},
+		{dir: "/mnt/o'brien", err: true},
+		{dir: `/mnt/"wal"`, err: true},
+		{dir: "/mnt/100%", err: true},
+		{dir: "/mnt/$HOME", err: true},
+		{dir: "/mnt/`id`", err: true},
+		{dir: `/mnt/wal\dir`, err: true},
+		{dir: "/mnt/wal\ndir", err: true},
+	}
+	for _, tt := range tests {
+		got, err := restoreCommand(tt.dir)
+		if tt.err {
+			if err == nil {
+				t.Errorf("restoreCommand(%q) = %q, want an error", tt.dir, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("restoreCommand(%q) = %q, %v; want %q", tt.dir, got, err, tt.want)
+		}
+	}
+}
+
+func TestExtractTarGz(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "base.tar.gz")
+	writeTarGz(t, archive, []tarEntry{
+		{name: "PG_VERSION", typeflag: tar.TypeReg, body: "16\n"},
+		{name: "global/", typeflag: tar.TypeDir, mode: 0o700},
+		{name: "global/pg_control", typeflag: tar.TypeReg, body: "control", mode: 0o600},
+		{name: "base/1/1259", typeflag: tar.TypeReg, body: "pg_class"}, // parent directories without entries
+		{name: "pg_wal/", typeflag: tar.TypeDir, mode: 0o700},
+		{name: "postgresql.conf", typeflag: tar.TypeReg, body: "port = 5432\n", mode: 0o644},
+	})
+
+	dir := filepath.Join(t.TempDir(), "data")
+	if err := extractTarGz(archive, dir); err != nil {
+		t.Fatalf("extractTarGz: %v", err)
+	}
+	for name, want := range map[string]string{
+		"PG_VERSION":        "16\n",
+		"global/pg_control": "control",
+		"base/1/1259":       "pg_class",
+		"postgresql.conf":   "port = 5432\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", name, data, err, want)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(dir, "pg_wal")); err != nil || !info.IsDir() {
+		t.Errorf("pg_wal is not a directory: %v", err)
+	}
+	if runtime.GOOS != "windows" {
+		if info, _ := os.Stat(filepath.Join(dir, "postgresql.conf")); info.Mode().Perm() != 0o644 {
+			t.Errorf("postgresql.conf mode = %v, want 0644", info.Mode().Perm())
+		}
+	}
+
+	// Extracting again into the same directory does not overwrite files
+	if err := extractTarGz(archive, dir); err == nil {
+		t.Error("extractTarGz overwrote an existing file")
+	}
+}
+
+func TestExtractTarGzUnsafePaths(t *testing.T) {
+	for _, name := range []string{"../escape", "base/../../escape", "/etc/escape"} {
+		t.Run(name, func(t *testing.T) {
+			archive := filepath.Join(t.TempDir(), "base.tar.gz")
+			writeTarGz(t, archive, []tarEntry{{name: name, typeflag: tar.TypeReg, body: "x"}})
+
+			root := t.TempDir()
+			err := extractTarGz(archive, filepath.Join(root, "data"))
+			if err == nil || !strings.Contains(err.Error(), "unsafe path") {
+				t.Errorf("extractTarGz error = %v, want an unsafe path error", err)
+			}
+			if _, err := os.Stat(filepath.Join(root, "escape")); err == nil {
+				t.Error("entry was written outside the data directory")
+			}
+		})
+	}
+}
+
+func TestCheckRecoveryTarget(t *testing.T) {
+	started := time.Date(2024, 5, 10, 2, 0, 0, 0, time.UTC)
+	finished := started.Add(30 * time.Minute)
+	backup := types.BaseBackup{StartedAt: started, FinishedAt: finished}
+	label := BaseBackupLabel{StartWAL: "000000010000000000000002", StartLSN: "0/2000028"}
+	tests := []struct {
+		name   string
+		backup types.BaseBackup
+		target RecoveryTarget
+		err    string
+	}{
+		{name: "no target", backup: backup},
+		{name: "time before the backup started", backup: backup, target: RecoveryTarget{Time: started.Add(-time.Minute)}, err: "before the base backup finished"},
+		{name: "time while the backup ran", backup: backup, target: RecoveryTarget{Time: started.Add(10 * time.Minute)}, err: "before the base backup finished"},
+		{name: "time when the backup finished", backup: backup, target: RecoveryTarget{Time: finished}},
+		{name: "time after the backup", backup: backup, target: RecoveryTarget{Time: finished.Add(time.Hour)}},
+		{name: "start time without a finish time", backup: types.BaseBackup{StartedAt: started}, target: RecoveryTarget{Time: started.Add(-time.Second)}, err: "before the base backup finished"},
+		{name: "no times in the catalog", target: RecoveryTarget{Time: started}},
+		{name: "lsn before the start", backup: backup, target: RecoveryTarget{LSN: "0/1FFFFFF"}, err: "before the base backup start 0/2000028"},
+		{name: "lsn at the start", backup: backup, target: RecoveryTarget{LSN: "0/2000028"}},
+		{name: "lsn on a later log", backup: backup, target: RecoveryTarget{LSN: "1/0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRecoveryTarget(tt.backup, label, tt.target)
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("checkRecoveryTarget: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("checkRecoveryTarget error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestExtractTarGzSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links need privileges on Windows")
+	}
+	outside := t.TempDir()
+
+	// A link outside pg_tblspc is skipped, so the next entry lands in a real directory
+	archive := filepath.Join(t.TempDir(), "base.tar.gz")
+	writeTarGz(t, archive, []tarEntry{
+		{name: "escape", typeflag: tar.TypeSymlink, linkname: outside},
+		{name: "escape/file", typeflag: tar.TypeReg, body: "x"},
+		{name: "pg_tblspc/16384", typeflag: tar.TypeSymlink, linkname: outside},
+	})
+	dir := filepath.Join(t.TempDir(), "data")
+	if err := extractTarGz(archive, dir); err != nil {
+		t.Fatalf("extractTarGz: %v", err)
+	}
+	if info, err := os.Lstat(filepath.Join(dir, "escape")); err != nil || !info.IsDir() {
+		t.Errorf("escape is not a plain directory: %v", err)
+	}
+	if target, err := os.Readlink(filepath.Join(dir, "pg_tblspc", "16384")); err != nil || target != outside {
+		t.Errorf("tablespace link = %q, %v; want %q", target, err, outside)
+	}
+
+	// Nothing may be written through a tablespace link
+	archive = filepath.Join(t.TempDir(), "base.tar.gz")
+	writeTarGz(t, archive, []tarEntry{
+		{name: "pg_tblspc/16384", typeflag: tar.TypeSymlink, linkname: outside},
+		{name: "pg_tblspc/16384/PG_16_202307071/5/16385", typeflag: tar.TypeReg, body: "x"},
+	})
+	dir = filepath.Join(t.TempDir(), "data")
+	if err := extractTarGz(archive, dir); err == nil || !strings.Contains(err.Error(), "symbolic link") {
+		t.Errorf("extractTarGz error = %v, want a symbolic link error", err)
+	}
+
+	entries, _ := os.ReadDir(outside)
+	if len(entries) > 0 {
+		t.Errorf("entries were written outside the data directory: %v", entries)
+	}
+}
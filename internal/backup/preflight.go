@@ -0,0 +1,144 @@
+package backup
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// Fallback estimates used when the catalog has no usable history
+const (
+	defaultCompressionRatio = 0.3
+	defaultThroughput       = 20 * 1024 * 1024 // bytes per second of source data
+
+	// Runs whose estimated output exceeds this share of the free space raise a warning
+	freeSpaceWarnRatio = 0.8
+)
+
+// Preflight estimates output size, free space and duration for the selected databases
+func (s *Service) Preflight(m types.Model) types.BackupPreflight {
+	var selected []types.DatabaseInfo
+	for i, db := range m.Choices {
+		if i > 0 {
+			selected = append(selected, m.DatabaseInfo[db])
+		}
+	}
+	return s.preflight(m, selected)
+}
+
+// SchemaPreflight estimates a per-schema run of dbname; each schema counts as one artifact,
+// estimated with the ratio of the database's full backups
+func (s *Service) SchemaPreflight(m types.Model, dbname string, schemas []types.SchemaInfo) types.BackupPreflight {
+	selected := make([]types.DatabaseInfo, 0, len(schemas))
+	for _, sc := range schemas {
+		selected = append(selected, types.DatabaseInfo{Name: dbname, Size: sc.Size})
+	}
+	return s.preflight(m, selected)
+}
+
+// preflight estimates a run producing one artifact per item of selected
+func (s *Service) preflight(m types.Model, selected []types.DatabaseInfo) types.BackupPreflight {
+	p := types.BackupPreflight{FreeSpace: -1}
+	p.Databases = len(selected)
+
+	conn := m.Connection()
+	ratios, globalRatio, throughput := s.history(conn.Host, conn.Port)
+
+	for _, info := range selected {
+		if info.Size < 0 {
+			p.UnknownSizes++
+			continue
+		}
+		ratio, ok := ratios[info.Name]
+		if !ok {
+			ratio = globalRatio
+		}
+		p.SourceSize += info.Size
+		p.EstimatedSize += int64(float64(info.Size) * ratio)
+	}
+	p.EstimatedDuration = time.Duration(float64(p.SourceSize) / throughput * float64(time.Second))
+
+	if conn.ServerVersion > 0 {
+		p.ServerVersion = FormatVersion(conn.ServerVersion)
+	}
+	// The destination is checked even without pg_dump, since the logical export writes there too
+	s.checkDestination(&p)
+
+	client, err := s.FindPgDump(m.PgDump, conn.ServerVersion)
+	if err != nil {
+		p.Blocked = true
+		p.Warning = strings.TrimSpace(err.Error() + "\n" + p.Warning)
+	} else {
+		p.PgDump = client.String()
+	}
+
+	s.logger.Info("backup pre-flight", "databases", p.Databases, "source_size", p.SourceSize,
+		"estimated_size", p.EstimatedSize, "free_space", p.FreeSpace, "blocked", p.Blocked)
+	return p
+}
+
+// checkDestination fills in the backup directory and its free space, blocking the run when the
+// estimated output does not fit
+func (s *Service) checkDestination(p *types.BackupPreflight) {
+	dir, err := s.BackupDir()
+	if err != nil {
+		p.Warning = err.Error()
+		return
+	}
+	p.Destination = dir
+
+	free, err := freeSpace(dir)
+	if err != nil {
+		s.logger.Warn("failed to check free space", "path", dir, "error", err)
+		p.Warning = "Não foi possível verificar o espaço livre no destino"
+		return
+	}
+	p.FreeSpace = free
+
+	switch {
+	case p.EstimatedSize > free:
+		p.Blocked = true
+		p.OutOfSpace = true
+		p.Warning = "Espaço livre insuficiente no destino para o tamanho estimado do backup"
+	case float64(p.EstimatedSize) > float64(free)*freeSpaceWarnRatio:
+		p.Warning = "O backup estimado ocupará mais de 80% do espaço livre no destino"
+	}
+}
+
+// history derives output/source ratios and throughput from previous backups on host
+func (s *Service) history(host, port string) (map[string]float64, float64, float64) {
+	ratios := make(map[string]float64)
+	globalRatio, throughput := defaultCompressionRatio, float64(defaultThroughput)
+
+	entries, err := s.catalog.Entries()
+	if err != nil {
+		s.logger.Error("failed to read backup catalog", "error", err)
+		return ratios, globalRatio, throughput
+	}
+
+	var totalSource, totalOutput int64
+	var totalSeconds float64
+	for _, e := range entries {
+		if !e.Success || !e.IsDump() || e.SourceSize <= 0 || e.OutputSize <= 0 {
+			continue
+		}
+
+		totalSource += e.SourceSize
+		totalOutput += e.OutputSize
+		totalSeconds += e.Duration().Seconds()
+
+		// Later entries overwrite earlier ones, so the latest full backup ratio wins
+		if e.Host == host && e.Port == port && e.Schema == "" {
+			ratios[e.Database] = float64(e.OutputSize) / float64(e.SourceSize)
+		}
+	}
+
+	if totalSource > 0 {
+		globalRatio = float64(totalOutput) / float64(totalSource)
+	}
+	if totalSeconds > 0 {
+		throughput = float64(totalSource) / totalSeconds
+	}
+	return ratios, globalRatio, throughput
+}
@@ -0,0 +1,180 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/catalog"
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// walFileName matches a WAL segment file name: timeline, log and segment, 8 hex digits each
+var walFileName = regexp.MustCompile(`^[0-9A-F]{24}`)
+
+// startWALPattern matches the START WAL LOCATION line of backup_label
+var startWALPattern = regexp.MustCompile(`^START WAL LOCATION: (\S+) \(file ([0-9A-F]{24})\)`)
+
+// BaseBackupLabel holds the fields of a base backup's backup_label used for recovery
+type BaseBackupLabel struct {
+	StartWAL  string // first WAL segment needed to recover the backup
+	StartLSN  string
+	StartTime string
+	Label     string
+}
+
+// ReadBackupLabel reads backup_label from base.tar.gz in a base backup directory. The server
+// sends it first, so only the start of the archive is decompressed.
+func ReadBackupLabel(backupDir string) (BaseBackupLabel, error) {
+	f, err := os.Open(filepath.Join(backupDir, "base.tar.gz"))
+	if err != nil {
+		return BaseBackupLabel{}, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return BaseBackupLabel{}, fmt.Errorf("failed to read base.tar.gz: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return BaseBackupLabel{}, errors.New("backup_label not found in base.tar.gz")
+		}
+		if err != nil {
+			return BaseBackupLabel{}, fmt.Errorf("failed to read base.tar.gz: %w", err)
+		}
+		if header.Name == "backup_label" {
+			return parseBackupLabel(tr)
+		}
+	}
+}
+
+// parseBackupLabel reads the "KEY: value" lines of a backup_label file
+func parseBackupLabel(r io.Reader) (BaseBackupLabel, error) {
+	var label BaseBackupLabel
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := startWALPattern.FindStringSubmatch(line); match != nil {
+			label.StartLSN, label.StartWAL = match[1], match[2]
+		}
+		if value, ok := strings.CutPrefix(line, "START TIME: "); ok {
+			label.StartTime = value
+		}
+		if value, ok := strings.CutPrefix(line, "LABEL: "); ok {
+			label.Label = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return label, err
+	}
+	if label.StartWAL == "" {
+		return label, errors.New("backup_label has no START WAL LOCATION")
+	}
+	return label, nil
+}
+
+// CleanWALArchive removes the archived WAL segments (and their .partial, .backup and compressed
+// variants) older than oldest, like pg_archivecleanup: the timeline is ignored and timeline
+// history files are kept. It returns how many files were removed.
+func CleanWALArchive(dir, oldest string) (int, error) {
+	if !walFileName.MatchString(oldest) {
+		return 0, fmt.Errorf("invalid WAL file name %q", oldest)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read WAL archive: %w", err)
+	}
+
+	removed := 0
+	for _, f := range files {
+		name := f.Name()
+		if !f.Type().IsRegular() || !walFileName.MatchString(name) || (len(name) > 24 && name[24] != '.') {
+			continue
+		}
+		if name[8:24] >= oldest[8:24] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// baseBackupEntries returns the successful base backups of the catalog whose directory still
+// exists, newest first
+func (s *Service) baseBackupEntries() ([]catalog.Entry, error) {
+	entries, err := s.catalog.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []catalog.Entry
+	for _, e := range entries {
+		if e.Kind != catalog.KindBase || !e.Success {
+			continue
+		}
+		if info, err := os.Stat(e.Path); err == nil && info.IsDir() {
+			backups = append(backups, e)
+		}
+	}
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].FinishedAt.After(backups[j].FinishedAt)
+	})
+	return backups, nil
+}
+
+// applyRetention removes the base backups of conn's server beyond the newest physical.Keep, then
+// the archived WAL older than the oldest base backup left. It returns the removed backup
+// directories and the number of WAL files removed.
+func (s *Service) applyRetention(conn types.DatabaseConnection, physical config.PhysicalSettings) ([]string, int, error) {
+	all, err := s.baseBackupEntries()
+	if err != nil {
+		return nil, 0, err
+	}
+	var backups []catalog.Entry
+	for _, e := range all {
+		if e.Host == conn.Host && e.Port == conn.Port {
+			backups = append(backups, e)
+		}
+	}
+
+	var removed []string
+	if physical.Keep > 0 && len(backups) > physical.Keep {
+		for _, e := range backups[physical.Keep:] {
+			if err := os.RemoveAll(e.Path); err != nil {
+				return removed, 0, fmt.Errorf("failed to remove base backup %s: %w", e.Path, err)
+			}
+			s.logger.Info("removed base backup by retention", "dir", e.Path)
+			removed = append(removed, filepath.Base(e.Path))
+		}
+		backups = backups[:physical.Keep]
+	}
+
+	if physical.WALArchiveDir == "" || len(backups) == 0 {
+		return removed, 0, nil
+	}
+	oldest := backups[len(backups)-1].Path
+	label, err := ReadBackupLabel(oldest)
+	if err != nil {
+		return removed, 0, fmt.Errorf("WAL archive not cleaned: %s: %w", filepath.Base(oldest), err)
+	}
+	count, err := CleanWALArchive(physical.WALArchiveDir, label.StartWAL)
+	if err != nil {
+		return removed, count, fmt.Errorf("WAL archive not fully cleaned: %w", err)
+	}
+	s.logger.Info("cleaned WAL archive", "dir", physical.WALArchiveDir, "oldest", label.StartWAL, "removed", count)
+	return removed, count, nil
+}
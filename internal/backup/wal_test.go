@@ -0,0 +1,192 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// tarEntry is one entry of a test archive
+type tarEntry struct {
+	name     string
+	typeflag byte
+	body     string
+	linkname string
+	mode     int64
+}
+
+// writeTarGz writes entries into a gzip-compressed tar archive at path
+func writeTarGz(t *testing.T, path string, entries []tarEntry) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Typeflag: e.typeflag, Linkname: e.linkname, Mode: e.mode, Size: int64(len(e.body))}
+		if header.Mode == 0 {
+			header.Mode = 0o600
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// backupLabel is the backup_label pg_basebackup writes on PostgreSQL 16
+const backupLabel = `START WAL LOCATION: 0/2000028 (file 000000010000000000000002)
+CHECKPOINT LOCATION: 0/2000060
+BACKUP METHOD: streamed
+BACKUP FROM: primary
+START TIME: 2024-05-10 02:00:00 UTC
+LABEL: snapTUI 20240510_020000
+START TIMELINE: 1
+`
+
+func TestParseBackupLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		label string
+		want  BaseBackupLabel
+		err   string
+	}{
+		{
+			name:  "streamed backup",
+			label: backupLabel,
+			want:  BaseBackupLabel{StartWAL: "000000010000000000000002", StartLSN: "0/2000028", StartTime: "2024-05-10 02:00:00 UTC", Label: "snapTUI 20240510_020000"},
+		},
+		{
+			name:  "later timeline",
+			label: "START WAL LOCATION: 1A/B3000028 (file 000000030000001A000000B3)\nSTART TIME: 2024-05-10 02:00:00 -03\n",
+			want:  BaseBackupLabel{StartWAL: "000000030000001A000000B3", StartLSN: "1A/B3000028", StartTime: "2024-05-10 02:00:00 -03"},
+		},
+		{
+			name:  "label with colons",
+			label: "START WAL LOCATION: 0/2000028 (file 000000010000000000000002)\nLABEL: nightly: full\n",
+			want:  BaseBackupLabel{StartWAL: "000000010000000000000002", StartLSN: "0/2000028", Label: "nightly: full"},
+		},
+		{name: "no start location", label: "START TIME: 2024-05-10 02:00:00 UTC\nLABEL: x\n", err: "no START WAL LOCATION"},
+		{name: "trailing characters in file name", label: "START WAL LOCATION: 0/2000028 (file 000000010000000000000002x)\n", err: "no START WAL LOCATION"},
+		{name: "short file name", label: "START WAL LOCATION: 0/2000028 (file 0000000100000000)\n", err: "no START WAL LOCATION"},
+		{name: "empty", label: "", err: "no START WAL LOCATION"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBackupLabel(strings.NewReader(tt.label))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("parseBackupLabel error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseBackupLabel: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("parseBackupLabel = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadBackupLabel(t *testing.T) {
+	dir := t.TempDir()
+	writeTarGz(t, filepath.Join(dir, "base.tar.gz"), []tarEntry{
+		{name: "PG_VERSION", typeflag: tar.TypeReg, body: "16\n"},
+		{name: "backup_label", typeflag: tar.TypeReg, body: backupLabel},
+	})
+	label, err := ReadBackupLabel(dir)
+	if err != nil {
+		t.Fatalf("ReadBackupLabel: %v", err)
+	}
+	if label.StartWAL != "000000010000000000000002" || label.StartLSN != "0/2000028" {
+		t.Errorf("ReadBackupLabel = %+v", label)
+	}
+
+	empty := t.TempDir()
+	writeTarGz(t, filepath.Join(empty, "base.tar.gz"), []tarEntry{{name: "PG_VERSION", typeflag: tar.TypeReg, body: "16\n"}})
+	if _, err := ReadBackupLabel(empty); err == nil || !strings.Contains(err.Error(), "backup_label not found") {
+		t.Errorf("ReadBackupLabel without backup_label error = %v", err)
+	}
+
+	if _, err := ReadBackupLabel(t.TempDir()); err == nil {
+		t.Error("ReadBackupLabel without base.tar.gz succeeded")
+	}
+}
+
+func TestCleanWALArchive(t *testing.T) {
+	files := map[string]bool{ // name to whether it is removed
+		"000000010000000000000001":                 true,
+		"000000010000000000000003":                 true,
+		"000000020000000000000002":                 true, // older segment on a later timeline
+		"000000010000000000000003.partial":         true,
+		"000000010000000000000003.00000028.backup": true,
+		"000000010000000000000003.gz":              true,
+		"000000010000000000000004":                 false, // the oldest segment itself
+		"000000010000000000000004.00000028.backup": false,
+		"000000010000000000000005.partial":         false,
+		"000000030000000000000004":                 false,
+		"000000010000000100000000":                 false, // next log file sorts after the oldest
+		"00000002.history":                         false,
+		"000000010000000000000001X":                false, // not a WAL file name
+		"notes.txt":                                false,
+	}
+	dir := t.TempDir()
+	for name := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Directories are never removed, even with a WAL file name
+	if err := os.Mkdir(filepath.Join(dir, "000000010000000000000002"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := CleanWALArchive(dir, "000000020000000000000004")
+	if err != nil {
+		t.Fatalf("CleanWALArchive: %v", err)
+	}
+
+	want := 0
+	for name, gone := range files {
+		_, err := os.Stat(filepath.Join(dir, name))
+		switch {
+		case gone && err == nil:
+			t.Errorf("%s was kept", name)
+		case !gone && err != nil:
+			t.Errorf("%s was removed", name)
+		}
+		if gone {
+			want++
+		}
+	}
+	if removed != want {
+		t.Errorf("CleanWALArchive removed %d files, want %d", removed, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "000000010000000000000002")); err != nil {
+		t.Error("directory with a WAL file name was removed")
+	}
+}
+
+func TestCleanWALArchiveInvalidOldest(t *testing.T) {
+	for _, oldest := range []string{"", "00000001000000000000000", "00000001.history", "0000000100000000000000zz"} {
+		if _, err := CleanWALArchive(t.TempDir(), oldest); err == nil || !strings.Contains(err.Error(), "invalid WAL file name") {
+			t.Errorf("CleanWALArchive(%q) error = %v, want an invalid name error", oldest, err)
+		}
+	}
+}
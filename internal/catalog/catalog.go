@@ -0,0 +1,133 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Backup kinds recorded in the catalog
+const (
+	KindDump   = "pg_dump"
+	KindExport = "export" // logical export made without pg_dump
+	KindBase   = "pg_basebackup"
+)
+
+// Entry represents a backup recorded in the catalog
+type Entry struct {
+	Profile    string    `json:"profile"`
+	Host       string    `json:"host"`
+	Port       string    `json:"port"`
+	Database   string    `json:"database"`
+	Schema     string    `json:"schema,omitempty"` // set when only this schema was dumped
+	Kind       string    `json:"kind,omitempty"`   // empty for entries written before kinds existed, meaning pg_dump
+	Path       string    `json:"path"`
+	SourceSize int64     `json:"source_size"`
+	OutputSize int64     `json:"output_size"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// IsDump reports whether the entry was made by pg_dump
+func (e Entry) IsDump() bool {
+	return e.Kind == "" || e.Kind == KindDump
+}
+
+// Duration returns how long the backup took
+func (e Entry) Duration() time.Duration {
+	return e.FinishedAt.Sub(e.StartedAt)
+}
+
+// Service keeps the backup history in a JSON file
+type Service struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewService creates a new catalog service backed by path
+func NewService(path string) *Service {
+	return &Service{path: path}
+}
+
+// Entries returns every recorded backup, oldest first
+func (s *Service) Entries() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Record appends an entry to the catalog
+func (s *Service) Record(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	return s.save(append(entries, entry))
+}
+
+// LastBackups returns the most recent successful full backup time of each database on host;
+// backups of a single schema are not counted
+func (s *Service) LastBackups(host, port string) (map[string]time.Time, error) {
+	entries, err := s.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	last := make(map[string]time.Time)
+	for _, e := range entries {
+		if !e.Success || e.Schema != "" || e.Host != host || e.Port != port {
+			continue
+		}
+		if e.FinishedAt.After(last[e.Database]) {
+			last[e.Database] = e.FinishedAt
+		}
+	}
+	return last, nil
+}
+
+// load reads the catalog file, returning no entries if it does not exist
+func (s *Service) load() ([]Entry, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse catalog %s: %w", s.path, err)
+	}
+	return entries, nil
+}
+
+// save writes the catalog atomically
+func (s *Service) save(entries []Entry) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create catalog directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode catalog: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write catalog: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace catalog: %w", err)
+	}
+	return nil
+}
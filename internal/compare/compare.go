@@ -0,0 +1,141 @@
+package compare
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/backup"
+	"github.com/Luiz-F3lipe/snapTUI/internal/catalog"
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/export"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Service compares the schemas of backups and live databases
+type Service struct {
+	logger  *slog.Logger
+	backup  *backup.Service
+	export  *export.Service
+	catalog *catalog.Service
+}
+
+// NewService creates a new compare service reading dumps with the clients found through backup
+// and live databases through export
+func NewService(logger *slog.Logger, backup *backup.Service, export *export.Service, catalog *catalog.Service) *Service {
+	return &Service{logger: logger, backup: backup, export: export, catalog: catalog}
+}
+
+// Sources lists the given live databases followed by the successful catalog entries whose file
+// still exists, newest first
+func (s *Service) Sources(databases []string) []types.CompareSource {
+	var sources []types.CompareSource
+	for _, db := range databases {
+		sources = append(sources, types.CompareSource{Label: "ao vivo: " + db, Database: db})
+	}
+
+	entries, err := s.catalog.Entries()
+	if err != nil {
+		s.logger.Warn("failed to read catalog", "error", err)
+	}
+	var files []types.CompareSource
+	for _, e := range entries {
+		if !e.Success || e.Path == "" || !(e.IsDump() || e.Kind == catalog.KindExport) {
+			continue
+		}
+		if info, err := os.Stat(e.Path); err != nil || info.IsDir() {
+			continue
+		}
+		files = append(files, types.CompareSource{
+			Label:     fmt.Sprintf("%s  %s  %s", e.Database, e.FinishedAt.Format("02/01/2006 15:04"), filepath.Base(e.Path)),
+			Path:      e.Path,
+			Kind:      e.Kind,
+			Database:  e.Database,
+			CreatedAt: e.FinishedAt,
+		})
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].CreatedAt.After(files[j].CreatedAt)
+	})
+	return append(sources, files...)
+}
+
+// Schema reads the tables of src: from the catalog of a live database, from schema.json of an
+// export archive, or from the schema-only script of a dump
+func (s *Service) Schema(ctx context.Context, src types.CompareSource, settings config.PgDumpSettings, serverVersion int) ([]export.Table, error) {
+	switch {
+	case src.Path == "":
+		return s.export.Schema(ctx, src.Database)
+	case src.Kind == catalog.KindExport:
+		return export.ReadSchema(src.Path)
+	case strings.EqualFold(filepath.Ext(src.Path), ".sql"):
+		// Plain dumps are already SQL scripts, which pg_restore cannot read
+		f, err := os.Open(src.Path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return ParseSchemaSQL(f)
+	}
+
+	pgRestore, err := s.backup.FindPgRestore(settings, serverVersion)
+	if err != nil {
+		return nil, err
+	}
+	cmd := pgRestore.CommandMounting([]string{filepath.Dir(src.Path)}, "--schema-only", "--file", "-", src.Path)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		s.logger.Error("pg_restore --schema-only failed", "file", src.Path, "error", err, "stderr", stderr.String())
+		return nil, fmt.Errorf("failed to read schema of %s: %w\nOutput: %s",
+			filepath.Base(src.Path), err, strings.TrimSpace(stderr.String()))
+	}
+	return ParseSchemaSQL(&stdout)
+}
+
+// CompareCmd creates a command that reads both schemas and reports their differences
+func (s *Service) CompareCmd(left, right types.CompareSource, settings config.PgDumpSettings, serverVersion int) tea.Cmd {
+	return func() tea.Msg {
+		msg := types.SchemaCompareMsg{Left: left.Label, Right: right.Label}
+		ctx := context.Background()
+
+		l, err := s.Schema(ctx, left, settings, serverVersion)
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		r, err := s.Schema(ctx, right, settings, serverVersion)
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+
+		msg.Changes = Diff(l, r)
+		s.logger.Info("schemas compared", "left", left.Label, "right", right.Label, "changes", len(msg.Changes))
+		return msg
+	}
+}
+
+// WriteText writes the comparison as a text report into dir and returns its path
+func WriteText(dir string, result types.SchemaCompareMsg) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Comparação de esquemas — %s\n\n", time.Now().Format("02/01/2006 15:04:05"))
+	fmt.Fprintf(&b, "A: %s\nB: %s\n\n", result.Left, result.Right)
+	b.WriteString("Alterações de A para B (+ adicionado, - removido, ~ alterado)\n\n")
+	for _, line := range Lines(result.Changes) {
+		b.WriteString(line + "\n")
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("schema_diff_%s.txt", time.Now().Format("20060102_150405")))
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write comparison: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,185 @@
+package compare
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/export"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// Change markers of a SchemaChange
+const (
+	ChangeAdded   = "+"
+	ChangeRemoved = "-"
+	ChangeAltered = "~"
+)
+
+// Compared object types
+const (
+	ObjectTable      = "tabela"
+	ObjectColumn     = "coluna"
+	ObjectConstraint = "restrição"
+	ObjectIndex      = "índice"
+)
+
+// Diff lists the changes that turn the left schema into the right one, table by table in name order.
+// A changed table is reported once, followed by the changes to its columns, constraints and indexes.
+func Diff(left, right []export.Table) []types.SchemaChange {
+	leftTables, rightTables := tablesByName(left), tablesByName(right)
+	names := make([]string, 0, len(leftTables)+len(rightTables))
+	for name := range leftTables {
+		names = append(names, name)
+	}
+	for name := range rightTables {
+		if _, ok := leftTables[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []types.SchemaChange
+	for _, name := range names {
+		l, inLeft := leftTables[name]
+		r, inRight := rightTables[name]
+		switch {
+		case !inLeft:
+			changes = append(changes, types.SchemaChange{Change: ChangeAdded, Object: ObjectTable, Table: name, Name: name})
+		case !inRight:
+			changes = append(changes, types.SchemaChange{Change: ChangeRemoved, Object: ObjectTable, Table: name, Name: name})
+		default:
+			tableChanges := diffTable(l, r)
+			if len(tableChanges) > 0 {
+				changes = append(changes, types.SchemaChange{Change: ChangeAltered, Object: ObjectTable, Table: name, Name: name})
+				changes = append(changes, tableChanges...)
+			}
+		}
+	}
+	return changes
+}
+
+// diffTable compares the columns, constraints and indexes of one table
+func diffTable(l, r export.Table) []types.SchemaChange {
+	table := l.QualifiedName()
+	var changes []types.SchemaChange
+	add := func(object string, lnames []string, ldefs map[string]string, rnames []string, rdefs map[string]string) {
+		for _, name := range rnames {
+			old, ok := ldefs[name]
+			switch {
+			case !ok:
+				changes = append(changes, types.SchemaChange{Change: ChangeAdded, Object: object, Table: table, Name: name, New: rdefs[name]})
+			case old != rdefs[name]:
+				changes = append(changes, types.SchemaChange{Change: ChangeAltered, Object: object, Table: table, Name: name, Old: old, New: rdefs[name]})
+			}
+		}
+		for _, name := range lnames {
+			if _, ok := rdefs[name]; !ok {
+				changes = append(changes, types.SchemaChange{Change: ChangeRemoved, Object: object, Table: table, Name: name, Old: ldefs[name]})
+			}
+		}
+	}
+
+	lnames, ldefs := columnDefs(l)
+	rnames, rdefs := columnDefs(r)
+	add(ObjectColumn, lnames, ldefs, rnames, rdefs)
+	lnames, ldefs = constraintDefs(l)
+	rnames, rdefs = constraintDefs(r)
+	add(ObjectConstraint, lnames, ldefs, rnames, rdefs)
+	lnames, ldefs = indexDefs(l)
+	rnames, rdefs = indexDefs(r)
+	add(ObjectIndex, lnames, ldefs, rnames, rdefs)
+	return changes
+}
+
+func tablesByName(tables []export.Table) map[string]export.Table {
+	m := make(map[string]export.Table, len(tables))
+	for _, t := range tables {
+		m[t.QualifiedName()] = t
+	}
+	return m
+}
+
+// columnDefs returns the column names in table order and their definitions
+func columnDefs(t export.Table) ([]string, map[string]string) {
+	names := make([]string, 0, len(t.Columns))
+	defs := make(map[string]string, len(t.Columns))
+	for _, c := range t.Columns {
+		def := c.Type
+		if c.NotNull {
+			def += " NOT NULL"
+		}
+		if c.Default != "" {
+			def += " DEFAULT " + c.Default
+		}
+		names = append(names, c.Name)
+		defs[c.Name] = def
+	}
+	return names, defs
+}
+
+// constraintDefs returns the constraint names and definitions. NOT NULL constraints, listed in
+// the catalog since PostgreSQL 18, are left out: they are compared as part of the columns.
+func constraintDefs(t export.Table) ([]string, map[string]string) {
+	var names []string
+	defs := make(map[string]string)
+	for _, c := range t.Constraints {
+		if strings.HasPrefix(c.Definition, "NOT NULL") {
+			continue
+		}
+		names = append(names, c.Name)
+		defs[c.Name] = c.Definition
+	}
+	sort.Strings(names)
+	return names, defs
+}
+
+// indexDefs returns the index names and definitions, leaving out the indexes that back a
+// constraint, which pg_dump does not write as separate statements
+func indexDefs(t export.Table) ([]string, map[string]string) {
+	constraints := make(map[string]bool, len(t.Constraints))
+	for _, c := range t.Constraints {
+		constraints[c.Name] = true
+	}
+
+	var names []string
+	defs := make(map[string]string)
+	for _, def := range t.Indexes {
+		rest := strings.TrimPrefix(strings.TrimPrefix(def, "CREATE UNIQUE INDEX "), "CREATE INDEX ")
+		name, _ := identifier(rest)
+		if constraints[name] {
+			continue
+		}
+		names = append(names, name)
+		defs[name] = def
+	}
+	sort.Strings(names)
+	return names, defs
+}
+
+// Lines formats changes for display and text export: one line per table, with the changes to its
+// columns, constraints and indexes indented below it
+func Lines(changes []types.SchemaChange) []string {
+	if len(changes) == 0 {
+		return []string{"Nenhuma diferença encontrada em tabelas, colunas, restrições e índices."}
+	}
+
+	var lines []string
+	for _, c := range changes {
+		if c.Object == ObjectTable {
+			lines = append(lines, fmt.Sprintf("%s %s %s", c.Change, ObjectTable, c.Table))
+			continue
+		}
+		line := fmt.Sprintf("    %s %s %s", c.Change, c.Object, c.Name)
+		switch c.Change {
+		case ChangeAdded:
+			line += ": " + c.New
+		case ChangeRemoved:
+			line += ": " + c.Old
+		default:
+			line += ": " + c.Old + " → " + c.New
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,236 @@
+package compare
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/export"
+)
+
+// dollarTag matches the opening tag of a dollar-quoted string, e.g. $$ or $_$
+var dollarTag = regexp.MustCompile(`\$[A-Za-z_]*\$`)
+
+// statementPrefixes are the pg_dump statements that describe tables, constraints and indexes
+var statementPrefixes = []string{"CREATE TABLE ", "CREATE UNLOGGED TABLE ", "ALTER TABLE ", "CREATE INDEX ", "CREATE UNIQUE INDEX "}
+
+// ParseSchemaSQL reads the tables, columns, constraints and indexes from the SQL script written
+// by pg_dump (or pg_restore -f) with --schema-only. Other statements are skipped, including the
+// contents of function bodies.
+func ParseSchemaSQL(r io.Reader) ([]export.Table, error) {
+	p := sqlParser{index: make(map[string]int)}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	var statement []string
+	quote := ""
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Inside a dollar-quoted body, wait for its closing tag
+		if quote != "" {
+			if strings.Count(line, quote)%2 == 1 {
+				quote = ""
+			}
+			continue
+		}
+
+		if statement == nil {
+			if !hasStatementPrefix(line) {
+				if tag := dollarTag.FindString(line); tag != "" && strings.Count(line, tag)%2 == 1 {
+					quote = tag
+				}
+				continue
+			}
+		}
+		statement = append(statement, line)
+		if strings.HasSuffix(strings.TrimSpace(line), ";") {
+			p.statement(statement)
+			statement = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read schema script: %w", err)
+	}
+	return p.tables, nil
+}
+
+func hasStatementPrefix(line string) bool {
+	for _, prefix := range statementPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sqlParser collects tables from pg_dump statements
+type sqlParser struct {
+	tables []export.Table
+	index  map[string]int // qualified name to position in tables
+}
+
+func (p *sqlParser) statement(lines []string) {
+	switch first := lines[0]; {
+	case strings.HasPrefix(first, "CREATE TABLE "), strings.HasPrefix(first, "CREATE UNLOGGED TABLE "):
+		p.createTable(lines)
+	case strings.HasPrefix(first, "ALTER TABLE "):
+		p.alterTable(joinLines(lines))
+	default:
+		p.createIndex(joinLines(lines))
+	}
+}
+
+// createTable parses a CREATE TABLE statement with one column or constraint per line
+func (p *sqlParser) createTable(lines []string) {
+	head := strings.TrimPrefix(strings.TrimPrefix(lines[0], "CREATE UNLOGGED TABLE "), "CREATE TABLE ")
+	schema, name, rest := qualifiedName(head)
+	// Only ordinary tables with a column list are compared, as in the live catalog query
+	if strings.TrimSpace(rest) != "(" {
+		return
+	}
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, "PARTITION BY ") {
+			return
+		}
+	}
+
+	t := export.Table{Schema: schema, Name: name}
+	for _, line := range lines[1:] {
+		item := strings.TrimSuffix(strings.TrimSpace(line), ",")
+		// Storage options and inheritance follow the closing parenthesis
+		if strings.HasPrefix(item, ")") {
+			break
+		}
+		if item == "" {
+			continue
+		}
+		if strings.HasPrefix(item, "CONSTRAINT ") {
+			cname, def := identifier(strings.TrimPrefix(item, "CONSTRAINT "))
+			t.Constraints = append(t.Constraints, export.Constraint{Name: cname, Definition: strings.TrimSpace(def)})
+			continue
+		}
+		t.Columns = append(t.Columns, parseColumn(item))
+	}
+	p.index[t.QualifiedName()] = len(p.tables)
+	p.tables = append(p.tables, t)
+}
+
+// parseColumn parses a column definition: name, type, then COLLATE, DEFAULT, GENERATED and NOT NULL clauses
+func parseColumn(item string) export.Column {
+	name, rest := identifier(item)
+	c := export.Column{Name: name}
+	rest = strings.TrimSpace(rest)
+
+	if strings.HasSuffix(rest, " NOT NULL") {
+		c.NotNull = true
+		rest = strings.TrimSuffix(rest, " NOT NULL")
+		// Named NOT NULL constraints are written as CONSTRAINT name NOT NULL
+		if i := strings.LastIndex(rest, " CONSTRAINT "); i >= 0 {
+			if _, after := identifier(rest[i+len(" CONSTRAINT "):]); after == "" {
+				rest = rest[:i]
+			}
+		}
+	}
+	if i := strings.Index(rest, " GENERATED ALWAYS AS ("); i >= 0 && strings.HasSuffix(rest, ") STORED") {
+		c.Default = rest[i+len(" GENERATED ALWAYS AS (") : len(rest)-len(") STORED")]
+		rest = rest[:i]
+	}
+	if i := strings.Index(rest, " DEFAULT "); i >= 0 {
+		c.Default = rest[i+len(" DEFAULT "):]
+		rest = rest[:i]
+	}
+	if i := strings.Index(rest, " COLLATE "); i >= 0 {
+		rest = rest[:i]
+	}
+	c.Type = rest
+	return c
+}
+
+// alterTable applies the constraints and column defaults pg_dump adds after creating tables
+func (p *sqlParser) alterTable(statement string) {
+	target := strings.TrimPrefix(strings.TrimPrefix(statement, "ALTER TABLE "), "ONLY ")
+	schema, name, rest := qualifiedName(target)
+	i, ok := p.index[schema+"."+name]
+	if !ok {
+		return
+	}
+	t := &p.tables[i]
+	rest = strings.TrimSpace(rest)
+
+	switch {
+	case strings.HasPrefix(rest, "ADD CONSTRAINT "):
+		cname, def := identifier(strings.TrimPrefix(rest, "ADD CONSTRAINT "))
+		t.Constraints = append(t.Constraints, export.Constraint{Name: cname, Definition: strings.TrimSpace(def)})
+	case strings.HasPrefix(rest, "ALTER COLUMN "):
+		column, action := identifier(strings.TrimPrefix(rest, "ALTER COLUMN "))
+		action = strings.TrimSpace(action)
+		if !strings.HasPrefix(action, "SET DEFAULT ") {
+			return
+		}
+		for j := range t.Columns {
+			if t.Columns[j].Name == column {
+				t.Columns[j].Default = strings.TrimPrefix(action, "SET DEFAULT ")
+			}
+		}
+	}
+}
+
+// createIndex adds a CREATE INDEX statement to the indexes of its table
+func (p *sqlParser) createIndex(statement string) {
+	i := strings.Index(statement, " ON ")
+	if i < 0 {
+		return
+	}
+	schema, name, _ := qualifiedName(strings.TrimPrefix(statement[i+4:], "ONLY "))
+	if j, ok := p.index[schema+"."+name]; ok {
+		p.tables[j].Indexes = append(p.tables[j].Indexes, statement)
+	}
+}
+
+// joinLines joins the trimmed lines of a statement and drops its final semicolon
+func joinLines(lines []string) string {
+	parts := make([]string, len(lines))
+	for i, line := range lines {
+		parts[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSuffix(strings.Join(parts, " "), ";")
+}
+
+// qualifiedName reads schema.name from the start of s and returns the remainder
+func qualifiedName(s string) (schema, name, rest string) {
+	schema, rest = identifier(s)
+	if !strings.HasPrefix(rest, ".") {
+		return "", schema, rest
+	}
+	name, rest = identifier(rest[1:])
+	return schema, name, rest
+}
+
+// identifier reads a possibly quoted identifier from the start of s and returns the remainder
+func identifier(s string) (string, string) {
+	if !strings.HasPrefix(s, `"`) {
+		end := strings.IndexAny(s, " .(,")
+		if end < 0 {
+			return s, ""
+		}
+		return s[:end], s[end:]
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] != '"' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '"' {
+			b.WriteByte('"')
+			i++
+			continue
+		}
+		return b.String(), s[i+1:]
+	}
+	return b.String(), ""
+}
@@ -0,0 +1,305 @@
+package compare
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/export"
+)
+
+// schemaDump is trimmed output of pg_restore --schema-only -f - for a PostgreSQL 18 dump
+const schemaDump = `--
+-- PostgreSQL database dump
+--
+
+SET statement_timeout = 0;
+SET client_encoding = 'UTF8';
+SELECT pg_catalog.set_config('search_path', '', false);
+
+CREATE SCHEMA "Sales";
+
+
+ALTER SCHEMA "Sales" OWNER TO postgres;
+
+--
+-- Name: make_log(); Type: FUNCTION; Schema: public; Owner: postgres
+--
+
+CREATE FUNCTION public.make_log() RETURNS void
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+CREATE TABLE public.fake (
+    id integer
+);
+ALTER TABLE ONLY public.customers ADD CONSTRAINT fake_pk PRIMARY KEY (id);
+END;
+$$;
+
+
+ALTER FUNCTION public.make_log() OWNER TO postgres;
+
+--
+-- Name: one(); Type: FUNCTION; Schema: public; Owner: postgres
+--
+
+CREATE FUNCTION public.one() RETURNS integer
+    LANGUAGE sql
+    AS $_$SELECT 1$_$;
+
+SET default_tablespace = '';
+
+--
+-- Name: Order Items; Type: TABLE; Schema: Sales; Owner: postgres
+--
+
+CREATE TABLE "Sales"."Order Items" (
+    "Item ID" bigint NOT NULL,
+    "Unit ""Price""" numeric(10,2) DEFAULT 0 NOT NULL,
+    note text COLLATE pg_catalog."C",
+    CONSTRAINT "positive price" CHECK (("Unit ""Price""" > (0)::numeric))
+);
+
+
+ALTER TABLE "Sales"."Order Items" OWNER TO postgres;
+
+--
+-- Name: customers; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.customers (
+    id integer CONSTRAINT customers_id_required NOT NULL,
+    email character varying(255) CONSTRAINT "email required" NOT NULL,
+    name text NOT NULL,
+    total numeric GENERATED ALWAYS AS ((id * 2)) STORED,
+    created_at timestamp with time zone DEFAULT now()
+);
+
+
+ALTER TABLE public.customers OWNER TO postgres;
+
+--
+-- Name: customers_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE public.customers_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+--
+-- Name: measurement; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.measurement (
+    city_id integer NOT NULL,
+    logdate date NOT NULL
+)
+PARTITION BY RANGE (logdate);
+
+--
+-- Name: measurement_y2024; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.measurement_y2024 (
+    city_id integer NOT NULL,
+    logdate date NOT NULL
+);
+
+--
+-- Name: customer_view; Type: VIEW; Schema: public; Owner: postgres
+--
+
+CREATE VIEW public.customer_view AS
+ SELECT id,
+    name
+   FROM public.customers;
+
+CREATE UNLOGGED TABLE public.cache (
+    key text NOT NULL
+)
+WITH (autovacuum_enabled='false');
+
+--
+-- Name: measurement_y2024; Type: TABLE ATTACH; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.measurement ATTACH PARTITION public.measurement_y2024 FOR VALUES FROM ('2024-01-01') TO ('2025-01-01');
+
+--
+-- Name: customers id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.customers ALTER COLUMN id SET DEFAULT nextval('public.customers_id_seq'::regclass);
+
+--
+-- Name: Order Items Order Items_pkey; Type: CONSTRAINT; Schema: Sales; Owner: postgres
+--
+
+ALTER TABLE ONLY "Sales"."Order Items"
+    ADD CONSTRAINT "Order Items_pkey" PRIMARY KEY ("Item ID");
+
+--
+-- Name: customers customers_email_key; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.customers
+    ADD CONSTRAINT customers_email_key UNIQUE (email);
+
+--
+-- Name: customers_name_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX customers_name_idx ON public.customers USING btree (lower(name));
+
+--
+-- Name: measurement_logdate_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX measurement_logdate_idx ON ONLY public.measurement USING btree (logdate);
+
+--
+-- Name: Order Items "Order Items_customer_fkey"; Type: FK CONSTRAINT; Schema: Sales; Owner: postgres
+--
+
+ALTER TABLE ONLY "Sales"."Order Items"
+    ADD CONSTRAINT "Order Items_customer_fkey" FOREIGN KEY ("Item ID") REFERENCES public.customers(id) ON DELETE CASCADE;
+
+--
+-- PostgreSQL database dump complete
+--
+`
+
+func TestParseSchemaSQL(t *testing.T) {
+	tables, err := ParseSchemaSQL(strings.NewReader(schemaDump))
+	if err != nil {
+		t.Fatalf("ParseSchemaSQL: %v", err)
+	}
+
+	want := []export.Table{
+		{
+			Schema: "Sales",
+			Name:   "Order Items",
+			Columns: []export.Column{
+				{Name: "Item ID", Type: "bigint", NotNull: true},
+				{Name: `Unit "Price"`, Type: "numeric(10,2)", NotNull: true, Default: "0"},
+				{Name: "note", Type: "text"},
+			},
+			Constraints: []export.Constraint{
+				{Name: "positive price", Definition: `CHECK (("Unit ""Price""" > (0)::numeric))`},
+				{Name: "Order Items_pkey", Definition: `PRIMARY KEY ("Item ID")`},
+				{Name: "Order Items_customer_fkey", Definition: `FOREIGN KEY ("Item ID") REFERENCES public.customers(id) ON DELETE CASCADE`},
+			},
+		},
+		{
+			Schema: "public",
+			Name:   "customers",
+			Columns: []export.Column{
+				{Name: "id", Type: "integer", NotNull: true, Default: "nextval('public.customers_id_seq'::regclass)"},
+				{Name: "email", Type: "character varying(255)", NotNull: true},
+				{Name: "name", Type: "text", NotNull: true},
+				{Name: "total", Type: "numeric", Default: "(id * 2)"},
+				{Name: "created_at", Type: "timestamp with time zone", Default: "now()"},
+			},
+			Constraints: []export.Constraint{
+				{Name: "customers_email_key", Definition: "UNIQUE (email)"},
+			},
+			Indexes: []string{"CREATE INDEX customers_name_idx ON public.customers USING btree (lower(name))"},
+		},
+		{
+			Schema: "public",
+			Name:   "measurement_y2024",
+			Columns: []export.Column{
+				{Name: "city_id", Type: "integer", NotNull: true},
+				{Name: "logdate", Type: "date", NotNull: true},
+			},
+		},
+		{
+			Schema:  "public",
+			Name:    "cache",
+			Columns: []export.Column{{Name: "key", Type: "text", NotNull: true}},
+		},
+	}
+
+	if len(tables) != len(want) {
+		names := make([]string, len(tables))
+		for i, tbl := range tables {
+			names[i] = tbl.QualifiedName()
+		}
+		t.Fatalf("parsed tables %v, want %d tables", names, len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(tables[i], want[i]) {
+			t.Errorf("table %s:\n got %+v\nwant %+v", want[i].QualifiedName(), tables[i], want[i])
+		}
+	}
+}
+
+func TestParseColumn(t *testing.T) {
+	tests := []struct {
+		item string
+		want export.Column
+	}{
+		{"id integer", export.Column{Name: "id", Type: "integer"}},
+		{"id integer NOT NULL", export.Column{Name: "id", Type: "integer", NotNull: true}},
+		{"id bigint CONSTRAINT id_required NOT NULL", export.Column{Name: "id", Type: "bigint", NotNull: true}},
+		{`id bigint CONSTRAINT "id required" NOT NULL`, export.Column{Name: "id", Type: "bigint", NotNull: true}},
+		{`id bigint CONSTRAINT "id ""required""" NOT NULL`, export.Column{Name: "id", Type: "bigint", NotNull: true}},
+		{"price numeric(10,2) DEFAULT 0 NOT NULL", export.Column{Name: "price", Type: "numeric(10,2)", NotNull: true, Default: "0"}},
+		{"price numeric(10,2) DEFAULT 0 CONSTRAINT price_required NOT NULL", export.Column{Name: "price", Type: "numeric(10,2)", NotNull: true, Default: "0"}},
+		{"status text DEFAULT 'NOT NULL'::text", export.Column{Name: "status", Type: "text", Default: "'NOT NULL'::text"}},
+		{`note text COLLATE pg_catalog."C" NOT NULL`, export.Column{Name: "note", Type: "text", NotNull: true}},
+		{`code text COLLATE "en_US" DEFAULT ''::text`, export.Column{Name: "code", Type: "text", Default: "''::text"}},
+		{"total integer GENERATED ALWAYS AS ((a + b)) STORED", export.Column{Name: "total", Type: "integer", Default: "(a + b)"}},
+		{`"Order ""Date""" timestamp(3) without time zone`, export.Column{Name: `Order "Date"`, Type: "timestamp(3) without time zone"}},
+		{`"select" character varying[]`, export.Column{Name: "select", Type: "character varying[]"}},
+	}
+	for _, tt := range tests {
+		if got := parseColumn(tt.item); got != tt.want {
+			t.Errorf("parseColumn(%q) = %+v, want %+v", tt.item, got, tt.want)
+		}
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		s, name, rest string
+	}{
+		{"customers (", "customers", " ("},
+		{"public.customers", "public", ".customers"},
+		{"id integer", "id", " integer"},
+		{"id", "id", ""},
+		{"customers_pkey PRIMARY KEY (id)", "customers_pkey", " PRIMARY KEY (id)"},
+		{`"Order Items" (`, "Order Items", " ("},
+		{`"Sales"."Order Items"`, "Sales", `."Order Items"`},
+		{`"a ""quoted"" name" text`, `a "quoted" name`, " text"},
+		{`"a.b"`, "a.b", ""},
+		{`"unterminated`, "unterminated", ""},
+	}
+	for _, tt := range tests {
+		name, rest := identifier(tt.s)
+		if name != tt.name || rest != tt.rest {
+			t.Errorf("identifier(%q) = %q, %q; want %q, %q", tt.s, name, rest, tt.name, tt.rest)
+		}
+	}
+}
+
+func TestQualifiedName(t *testing.T) {
+	tests := []struct {
+		s, schema, name, rest string
+	}{
+		{"public.customers (", "public", "customers", " ("},
+		{`"Sales"."Order Items" OWNER TO postgres;`, "Sales", "Order Items", " OWNER TO postgres;"},
+		{"customers (", "", "customers", " ("},
+	}
+	for _, tt := range tests {
+		schema, name, rest := qualifiedName(tt.s)
+		if schema != tt.schema || name != tt.name || rest != tt.rest {
+			t.Errorf("qualifiedName(%q) = %q, %q, %q; want %q, %q, %q", tt.s, schema, name, rest, tt.schema, tt.name, tt.rest)
+		}
+	}
+}
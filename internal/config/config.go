@@ -86,4 +86,11 @@ const (
 	DefaultPort     = "5432"
 	DefaultDatabase = "postgres"
 	TitleWidth      = 80
+
+	DefaultConnectTimeout = 10 // seconds
+
+	DefaultMaxConcurrentPerHost = 1
+
+	DefaultLogMaxSizeMB  = 10
+	DefaultLogMaxBackups = 5
 )
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"path"
+)
+
+// Masking actions
+const (
+	MaskNull   = "null"   // replace with NULL
+	MaskHash   = "hash"   // replace with a keyed SHA-256 hex digest
+	MaskEmail  = "email"  // replace with a fake address derived from the value
+	MaskRedact = "redact" // replace every character but the last Keep with '*'
+	MaskFixed  = "fixed"  // replace with Value
+)
+
+// MaskingProfile is a named set of masking rules applied to logical exports
+type MaskingProfile struct {
+	Name  string     `json:"name"`
+	Salt  string     `json:"salt"` // key for hash and email, so digests cannot be matched against known values
+	Rules []MaskRule `json:"rules"`
+}
+
+// MaskRule masks the columns matching Table and Column. Both accept shell wildcards;
+// Table matches either schema.table or the bare table name.
+type MaskRule struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	Action string `json:"action"`
+	Value  string `json:"value,omitempty"` // fixed replacement
+	Keep   int    `json:"keep,omitempty"`  // trailing characters left visible by redact
+}
+
+// Validate checks the actions and patterns of the profile's rules
+func (p MaskingProfile) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("masking profile name is required")
+	}
+	for i, r := range p.Rules {
+		switch r.Action {
+		case MaskNull, MaskHash, MaskEmail, MaskRedact, MaskFixed:
+		default:
+			return fmt.Errorf("masking profile %q: rule #%d: unknown action %q", p.Name, i+1, r.Action)
+		}
+		if r.Table == "" || r.Column == "" {
+			return fmt.Errorf("masking profile %q: rule #%d: table and column are required", p.Name, i+1)
+		}
+		for _, pattern := range []string{r.Table, r.Column} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("masking profile %q: rule #%d: invalid pattern %q", p.Name, i+1, pattern)
+			}
+		}
+		if r.Keep < 0 {
+			return fmt.Errorf("masking profile %q: rule #%d: keep must not be negative", p.Name, i+1)
+		}
+	}
+	return nil
+}
+
+// Match returns the first rule matching the column of schema.table, if any
+func (p MaskingProfile) Match(schema, table, column string) (MaskRule, bool) {
+	for _, r := range p.Rules {
+		tableMatch, _ := path.Match(r.Table, schema+"."+table)
+		if !tableMatch {
+			tableMatch, _ = path.Match(r.Table, table)
+		}
+		if columnMatch, _ := path.Match(r.Column, column); tableMatch && columnMatch {
+			return r, true
+		}
+	}
+	return MaskRule{}, false
+}
+
+// MaskingProfile returns the masking profile with the given name
+func (s *Settings) MaskingProfile(name string) (MaskingProfile, error) {
+	for _, p := range s.MaskingProfiles {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return MaskingProfile{}, fmt.Errorf("masking profile %q not found", name)
+}
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// pg_dump output formats
+const (
+	DumpFormatCustom = "custom"
+	DumpFormatTar    = "tar"
+	DumpFormatPlain  = "plain"
+)
+
+// Client execution runtimes
+const (
+	RuntimeLocal  = "local"
+	RuntimeDocker = "docker"
+	RuntimePodman = "podman"
+)
+
+// DefaultClientImage is the image whose tags match PostgreSQL major versions
+const DefaultClientImage = "docker.io/library/postgres"
+
+// PgDumpSettings selects the pg_dump binary, output format and extra arguments of a profile.
+// Path, Runtime and Image also apply to pg_restore.
+type PgDumpSettings struct {
+	Path   string   `json:"path"`   // explicit binary, skipping discovery
+	Format string   `json:"format"` // custom (default), tar or plain
+	Args   []string `json:"args"`
+
+	// Runtime is local, docker or podman; empty uses local clients and falls back to a container
+	Runtime string `json:"runtime"`
+	Image   string `json:"image"` // tagged with the server major version
+}
+
+// dumpFlag describes a pg_dump option accepted in extra arguments
+type dumpFlag struct {
+	value   bool     // whether the option takes a value
+	formats []string // formats the option applies to, nil for all
+}
+
+// dumpFlags lists the pg_dump options accepted in extra arguments, by long name
+var dumpFlags = map[string]dumpFlag{
+	"--data-only":                       {},
+	"--large-objects":                   {},
+	"--blobs":                           {},
+	"--no-large-objects":                {},
+	"--no-blobs":                        {},
+	"--clean":                           {},
+	"--create":                          {},
+	"--if-exists":                       {},
+	"--no-owner":                        {},
+	"--disable-triggers":                {},
+	"--use-set-session-authorization":   {},
+	"--extension":                       {value: true},
+	"--exclude-extension":               {value: true},
+	"--encoding":                        {value: true},
+	"--exclude-schema":                  {value: true},
+	"--schema-only":                     {},
+	"--superuser":                       {value: true},
+	"--table":                           {value: true},
+	"--exclude-table":                   {value: true},
+	"--table-and-children":              {value: true},
+	"--exclude-table-and-children":      {value: true},
+	"--exclude-table-data":              {value: true},
+	"--exclude-table-data-and-children": {value: true},
+	"--verbose":                         {},
+	"--no-privileges":                   {},
+	"--no-acl":                          {},
+	"--compress":                        {value: true, formats: []string{DumpFormatCustom, DumpFormatPlain}},
+	"--column-inserts":                  {},
+	"--attribute-inserts":               {},
+	"--disable-dollar-quoting":          {},
+	"--enable-row-security":             {},
+	"--extra-float-digits":              {value: true},
+	"--filter":                          {value: true},
+	"--include-foreign-data":            {value: true},
+	"--inserts":                         {},
+	"--load-via-partition-root":         {},
+	"--lock-wait-timeout":               {value: true},
+	"--no-comments":                     {},
+	"--no-publications":                 {},
+	"--no-security-labels":              {},
+	"--no-subscriptions":                {},
+	"--no-sync":                         {},
+	"--no-table-access-method":          {},
+	"--no-tablespaces":                  {},
+	"--no-toast-compression":            {},
+	"--no-unlogged-table-data":          {},
+	"--on-conflict-do-nothing":          {},
+	"--quote-all-identifiers":           {},
+	"--rows-per-insert":                 {value: true},
+	"--section":                         {value: true},
+	"--serializable-deferrable":         {},
+	"--snapshot":                        {value: true},
+	"--strict-names":                    {},
+	"--role":                            {value: true},
+}
+
+// dumpShortFlags maps single-letter pg_dump options to their long names
+var dumpShortFlags = map[string]string{
+	"-a": "--data-only",
+	"-b": "--large-objects",
+	"-B": "--no-large-objects",
+	"-c": "--clean",
+	"-C": "--create",
+	"-e": "--extension",
+	"-E": "--encoding",
+	"-N": "--exclude-schema",
+	"-O": "--no-owner",
+	"-s": "--schema-only",
+	"-S": "--superuser",
+	"-t": "--table",
+	"-T": "--exclude-table",
+	"-v": "--verbose",
+	"-x": "--no-privileges",
+	"-Z": "--compress",
+}
+
+// managedDumpFlags are set by snapTUI itself and cannot be passed as extra arguments. --schema
+// is set by per-schema backups, where a profile-level one would add a second schema to each file.
+var managedDumpFlags = []string{
+	"-f", "--file", "-F", "--format", "-h", "--host", "-p", "--port",
+	"-U", "--username", "-d", "--dbname", "-w", "--no-password", "-W", "--password",
+	"-n", "--schema",
+}
+
+// FormatOrDefault returns the configured format, defaulting to custom
+func (p PgDumpSettings) FormatOrDefault() string {
+	if p.Format == "" {
+		return DumpFormatCustom
+	}
+	return p.Format
+}
+
+// ImageOrDefault returns the configured client image, defaulting to the official postgres image
+func (p PgDumpSettings) ImageOrDefault() string {
+	if p.Image == "" {
+		return DefaultClientImage
+	}
+	return p.Image
+}
+
+// Validate checks the format and that every extra argument is a pg_dump option supported by it
+func (p PgDumpSettings) Validate() error {
+	format := p.FormatOrDefault()
+	switch format {
+	case DumpFormatCustom, DumpFormatTar, DumpFormatPlain:
+	default:
+		return fmt.Errorf("pg_dump: unsupported format %q (use %s, %s or %s)", p.Format, DumpFormatCustom, DumpFormatTar, DumpFormatPlain)
+	}
+
+	switch p.Runtime {
+	case "", RuntimeLocal:
+	case RuntimeDocker, RuntimePodman:
+		if p.Path != "" {
+			return fmt.Errorf("pg_dump: path cannot be combined with the %s runtime", p.Runtime)
+		}
+	default:
+		return fmt.Errorf("pg_dump: unsupported runtime %q (use %s, %s or %s)", p.Runtime, RuntimeLocal, RuntimeDocker, RuntimePodman)
+	}
+	// A registry port may precede the last slash; a tag may only follow it
+	if name := p.Image[strings.LastIndex(p.Image, "/")+1:]; strings.Contains(name, ":") {
+		return fmt.Errorf("pg_dump: image %q must not include a tag; it is chosen from the server version", p.Image)
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < len(p.Args); i++ {
+		arg := p.Args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" || arg == "--" {
+			return fmt.Errorf("pg_dump: unexpected argument %q", arg)
+		}
+
+		// Split "--name=value" and "-Xvalue" into the option and its inline value
+		name, value, inline := arg, "", false
+		if strings.HasPrefix(arg, "--") {
+			name, value, inline = strings.Cut(arg, "=")
+		} else if len(arg) > 2 {
+			name, value, inline = arg[:2], arg[2:], true
+		}
+
+		if slices.Contains(managedDumpFlags, name) {
+			return fmt.Errorf("pg_dump: option %s is managed by snapTUI and cannot be set", name)
+		}
+		if long, ok := dumpShortFlags[name]; ok {
+			name = long
+		}
+		flag, ok := dumpFlags[name]
+		if !ok {
+			return fmt.Errorf("pg_dump: unknown option %q", arg)
+		}
+		if flag.formats != nil && !slices.Contains(flag.formats, format) {
+			return fmt.Errorf("pg_dump: option %s is not supported by the %s format", name, format)
+		}
+
+		switch {
+		case flag.value && !inline:
+			if i+1 >= len(p.Args) {
+				return fmt.Errorf("pg_dump: option %s requires a value", name)
+			}
+			i++
+		case flag.value && value == "":
+			return fmt.Errorf("pg_dump: option %s requires a value", name)
+		case !flag.value && inline:
+			return fmt.Errorf("pg_dump: option %s does not take a value", name)
+		}
+		seen[name] = true
+	}
+
+	if seen["--if-exists"] && !seen["--clean"] {
+		return fmt.Errorf("pg_dump: option --if-exists requires --clean")
+	}
+	if seen["--data-only"] && seen["--schema-only"] {
+		return fmt.Errorf("pg_dump: options --data-only and --schema-only cannot be used together")
+	}
+	return nil
+}
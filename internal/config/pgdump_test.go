@@ -0,0 +1,44 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPgDumpSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings PgDumpSettings
+		err      string
+	}{
+		{"no-owner in the default format", PgDumpSettings{Args: []string{"--no-owner"}}, ""},
+		{"short no-owner in tar", PgDumpSettings{Format: DumpFormatTar, Args: []string{"-O"}}, ""},
+		{"clean and if-exists in custom", PgDumpSettings{Args: []string{"--clean", "--if-exists", "--create"}}, ""},
+		{"disable-triggers in custom", PgDumpSettings{Args: []string{"--data-only", "--disable-triggers"}}, ""},
+		{"plain options", PgDumpSettings{Format: DumpFormatPlain, Args: []string{"-c", "--if-exists", "-x", "--lock-wait-timeout=30s"}}, ""},
+		{"values inline and separate", PgDumpSettings{Args: []string{"--exclude-table-data=logs.*", "-T", "audit.*", "-Z6"}}, ""},
+		{"if-exists without clean", PgDumpSettings{Args: []string{"--if-exists"}}, "--if-exists requires --clean"},
+		{"data and schema only", PgDumpSettings{Args: []string{"-a", "-s"}}, "cannot be used together"},
+		{"schema", PgDumpSettings{Args: []string{"--schema", "public"}}, "option --schema is managed by snapTUI"},
+		{"inline schema", PgDumpSettings{Args: []string{"--schema=public"}}, "option --schema is managed by snapTUI"},
+		{"short schema", PgDumpSettings{Args: []string{"-npublic"}}, "option -n is managed by snapTUI"},
+		{"jobs", PgDumpSettings{Args: []string{"--jobs=4"}}, `unknown option "--jobs=4"`},
+		{"short jobs", PgDumpSettings{Args: []string{"-j", "4"}}, `unknown option "-j"`},
+		{"compress in tar", PgDumpSettings{Format: DumpFormatTar, Args: []string{"--compress=6"}}, "not supported by the tar format"},
+		{"managed file", PgDumpSettings{Args: []string{"-f", "out.sql"}}, "option -f is managed by snapTUI"},
+		{"missing value", PgDumpSettings{Args: []string{"--exclude-table"}}, "requires a value"},
+		{"unexpected value", PgDumpSettings{Args: []string{"--no-owner=yes"}}, "does not take a value"},
+		{"unknown format", PgDumpSettings{Format: "directory"}, `unsupported format "directory"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate()
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("Validate: %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Errorf("Validate error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Settings represents the user configuration file
+type Settings struct {
+	Profiles    []Profile   `json:"profiles"`
+	Log         LogSettings `json:"log"`
+	CatalogPath string      `json:"catalog_path"`
+
+	// MaskingProfiles are the anonymization rule sets offered for logical exports
+	MaskingProfiles []MaskingProfile `json:"masking_profiles"`
+
+	// MaxConcurrentPerHost limits parallel dumps against one server in multi-server runs
+	MaxConcurrentPerHost int `json:"max_concurrent_per_host"`
+}
+
+// LogSettings configures the structured log file
+type LogSettings struct {
+	Path       string `json:"path"`
+	Level      string `json:"level"`
+	Format     string `json:"format"`
+	MaxSizeMB  int    `json:"max_size_mb"`
+	MaxBackups int    `json:"max_backups"`
+}
+
+// Profile represents a named connection and its options
+type Profile struct {
+	Name           string               `json:"name"`
+	Host           string               `json:"host"`
+	Port           string               `json:"port"`
+	User           string               `json:"user"`
+	Password       string               `json:"password"`
+	Database       string               `json:"database"`
+	SSLMode        string               `json:"sslmode"`
+	ConnectTimeout int                  `json:"connect_timeout"`
+	Notifications  NotificationSettings `json:"notifications"`
+	SSH            *SSHSettings         `json:"ssh,omitempty"`
+	PgDump         PgDumpSettings       `json:"pg_dump"`
+	Physical       PhysicalSettings     `json:"physical"`
+
+	// Production blocks restores into the profile's server unless AllowRestore is set
+	Production   bool `json:"production"`
+	AllowRestore bool `json:"allow_restore"`
+
+	// Masking names the masking profile preselected for logical exports
+	Masking string `json:"masking"`
+}
+
+// PhysicalSettings configures pg_basebackup runs and the WAL archive of a profile
+type PhysicalSettings struct {
+	// WALArchiveDir is where the server's archive_command copies WAL segments; segments older
+	// than the oldest kept base backup are removed from it
+	WALArchiveDir string `json:"wal_archive_dir"`
+	// Keep is the number of base backups of the server kept on disk, 0 for all
+	Keep int `json:"keep"`
+}
+
+// SSHSettings configures an SSH tunnel through a bastion host
+type SSHSettings struct {
+	Host       string `json:"host"`
+	Port       string `json:"port"`
+	User       string `json:"user"`
+	KeyFile    string `json:"key_file"`
+	KnownHosts string `json:"known_hosts"`
+}
+
+// NotificationSettings holds the notification targets of a profile
+type NotificationSettings struct {
+	Targets []NotificationTarget `json:"targets"`
+}
+
+// Notification target types
+const (
+	NotifyWebhook = "webhook"
+	NotifySlack   = "slack"
+	NotifyEmail   = "email"
+)
+
+// NotificationTarget represents a single notification destination
+type NotificationTarget struct {
+	Type      string `json:"type"`
+	OnSuccess bool   `json:"on_success"`
+	OnFailure bool   `json:"on_failure"`
+
+	// Webhook and Slack
+	URL string `json:"url,omitempty"`
+
+	// Email
+	SMTPHost     string   `json:"smtp_host,omitempty"`
+	SMTPPort     string   `json:"smtp_port,omitempty"`
+	SMTPUser     string   `json:"smtp_user,omitempty"`
+	SMTPPassword string   `json:"smtp_password,omitempty"`
+	From         string   `json:"from,omitempty"`
+	To           []string `json:"to,omitempty"`
+}
+
+// DefaultSettingsPath returns the default location of the configuration file
+func DefaultSettingsPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "snaptui.json"
+	}
+	return filepath.Join(dir, "snaptui", "config.json")
+}
+
+// DefaultLogPath returns the default location of the log file
+func DefaultLogPath() string {
+	return filepath.Join(filepath.Dir(DefaultSettingsPath()), "snaptui.log")
+}
+
+// DefaultCatalogPath returns the default location of the backup catalog
+func DefaultCatalogPath() string {
+	return filepath.Join(filepath.Dir(DefaultSettingsPath()), "catalog.json")
+}
+
+// LoadSettings reads the configuration file, returning empty settings if it does not exist
+func LoadSettings(path string) (*Settings, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return (&Settings{}).withDefaults(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	var settings Settings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse settings %s: %w", path, err)
+	}
+
+	for i, target := range settings.allTargets() {
+		switch target.Type {
+		case NotifyWebhook, NotifySlack, NotifyEmail:
+		default:
+			return nil, fmt.Errorf("invalid notification target #%d: unknown type %q", i+1, target.Type)
+		}
+		// A target with neither policy would silently never fire
+		if !target.OnSuccess && !target.OnFailure {
+			return nil, fmt.Errorf("invalid notification target #%d: enable on_success, on_failure or both", i+1)
+		}
+	}
+
+	for _, p := range settings.MaskingProfiles {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, p := range settings.Profiles {
+		if p.Masking != "" {
+			if _, err := settings.MaskingProfile(p.Masking); err != nil {
+				return nil, fmt.Errorf("profile %q: %w", p.Name, err)
+			}
+		}
+		if p.SSH != nil && p.SSH.Host == "" {
+			return nil, fmt.Errorf("profile %q: ssh host is required", p.Name)
+		}
+		if err := p.PgDump.Validate(); err != nil {
+			return nil, fmt.Errorf("profile %q: %w", p.Name, err)
+		}
+		if p.Physical.Keep < 0 {
+			return nil, fmt.Errorf("profile %q: physical keep must not be negative", p.Name)
+		}
+	}
+
+	return settings.withDefaults(), nil
+}
+
+// withDefaults fills empty options with default values
+func (s *Settings) withDefaults() *Settings {
+	if s.CatalogPath == "" {
+		s.CatalogPath = DefaultCatalogPath()
+	}
+	if s.MaxConcurrentPerHost <= 0 {
+		s.MaxConcurrentPerHost = DefaultMaxConcurrentPerHost
+	}
+	if s.Log.Path == "" {
+		s.Log.Path = DefaultLogPath()
+	}
+	if s.Log.Level == "" {
+		s.Log.Level = "info"
+	}
+	if s.Log.MaxSizeMB == 0 {
+		s.Log.MaxSizeMB = DefaultLogMaxSizeMB
+	}
+	if s.Log.MaxBackups == 0 {
+		s.Log.MaxBackups = DefaultLogMaxBackups
+	}
+	return s
+}
+
+// Profile returns the profile with the given name, or a default profile when name is empty
+func (s *Settings) Profile(name string) (Profile, error) {
+	if name == "" {
+		if len(s.Profiles) > 0 {
+			return s.Profiles[0].withDefaults(), nil
+		}
+		return Profile{}.withDefaults(), nil
+	}
+
+	for _, p := range s.Profiles {
+		if p.Name == name {
+			return p.withDefaults(), nil
+		}
+	}
+	return Profile{}, fmt.Errorf("profile %q not found", name)
+}
+
+// withDefaults fills empty connection fields with default values
+func (p Profile) withDefaults() Profile {
+	if p.Name == "" {
+		p.Name = "default"
+	}
+	if p.Host == "" {
+		p.Host = DefaultHost
+	}
+	if p.Port == "" {
+		p.Port = DefaultPort
+	}
+	if p.Database == "" {
+		p.Database = DefaultDatabase
+	}
+	if p.ConnectTimeout <= 0 {
+		p.ConnectTimeout = DefaultConnectTimeout
+	}
+	p.PgDump.Format = p.PgDump.FormatOrDefault()
+	return p
+}
+
+// allTargets returns the notification targets of every profile
+func (s *Settings) allTargets() []NotificationTarget {
+	var targets []NotificationTarget
+	for _, p := range s.Profiles {
+		targets = append(targets, p.Notifications.Targets...)
+	}
+	return targets
+}
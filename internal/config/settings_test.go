@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSettingsNotificationTargets(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		err    string
+	}{
+		{"success policy", `{"type": "webhook", "url": "http://localhost", "on_success": true}`, ""},
+		{"failure policy", `{"type": "slack", "url": "http://localhost", "on_failure": true}`, ""},
+		{"no policy", `{"type": "webhook", "url": "http://localhost"}`, "enable on_success, on_failure or both"},
+		{"policies disabled", `{"type": "webhook", "url": "http://localhost", "on_success": false, "on_failure": false}`, "enable on_success, on_failure or both"},
+		{"unknown type", `{"type": "sms", "on_failure": true}`, `unknown type "sms"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			data := `{"profiles": [{"name": "producao", "notifications": {"targets": [` + tt.target + `]}}]}`
+			if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadSettings(path)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("LoadSettings: %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Errorf("LoadSettings error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
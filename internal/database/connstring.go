@@ -0,0 +1,203 @@
+package database
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// ParseConnString parses a libpq URI (postgres://...) or key=value DSN into connection parameters
+func ParseConnString(s string) (types.DatabaseConnection, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return types.DatabaseConnection{}, fmt.Errorf("empty connection string")
+	}
+
+	var params map[string]string
+	var err error
+	if strings.HasPrefix(s, "postgres://") || strings.HasPrefix(s, "postgresql://") {
+		params, err = parseURI(s)
+	} else {
+		params, err = parseDSN(s)
+	}
+	if err != nil {
+		return types.DatabaseConnection{}, err
+	}
+
+	conn := types.DatabaseConnection{
+		Host:     params["host"],
+		Port:     params["port"],
+		User:     params["user"],
+		Password: params["password"],
+		Database: params["dbname"],
+		SSLMode:  params["sslmode"],
+	}
+	if timeout, ok := params["connect_timeout"]; ok {
+		seconds, err := strconv.Atoi(timeout)
+		if err != nil {
+			return types.DatabaseConnection{}, fmt.Errorf("invalid connect_timeout %q", timeout)
+		}
+		conn.ConnectTimeout = time.Duration(seconds) * time.Second
+	}
+	if strings.Contains(conn.Host, ",") {
+		return types.DatabaseConnection{}, fmt.Errorf("multiple hosts are not supported")
+	}
+	return conn, nil
+}
+
+// ClientEnvVars are the libpq variables ClientEnv sets, passed through to containerized clients
+var ClientEnvVars = []string{"PGPASSWORD", "PGSSLMODE", "PGCONNECT_TIMEOUT"}
+
+// ClientEnv returns the environment for pg_dump, pg_restore, psql and pg_basebackup connecting
+// with conn: the password, SSL mode and connect timeout are passed as libpq variables, with the
+// same defaults as the application's own connection
+func ClientEnv(conn types.DatabaseConnection) []string {
+	sslMode := conn.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	env := append(os.Environ(), "PGPASSWORD="+conn.Password, "PGSSLMODE="+sslMode)
+	if conn.ConnectTimeout > 0 {
+		env = append(env, "PGCONNECT_TIMEOUT="+strconv.Itoa(max(1, int(conn.ConnectTimeout.Seconds()))))
+	}
+	return env
+}
+
+// RedactedURI renders conn as a postgres:// URI with the password masked
+func RedactedURI(conn types.DatabaseConnection) string {
+	u := url.URL{
+		Scheme: "postgres",
+		Host:   conn.Host,
+		Path:   "/" + conn.Database,
+	}
+	if conn.Port != "" {
+		u.Host = net.JoinHostPort(conn.Host, conn.Port)
+	}
+	switch {
+	case conn.User != "" && conn.Password != "":
+		u.User = url.UserPassword(conn.User, "xxxxx")
+	case conn.User != "":
+		u.User = url.User(conn.User)
+	}
+
+	query := url.Values{}
+	if conn.SSLMode != "" {
+		query.Set("sslmode", conn.SSLMode)
+	}
+	if conn.ConnectTimeout > 0 {
+		query.Set("connect_timeout", strconv.Itoa(int(conn.ConnectTimeout.Seconds())))
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+// parseURI extracts connection parameters from a postgres:// URI
+func parseURI(s string) (map[string]string, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection URI: %w", err)
+	}
+
+	params := make(map[string]string)
+	if u.User != nil {
+		params["user"] = u.User.Username()
+		if password, ok := u.User.Password(); ok {
+			params["password"] = password
+		}
+	}
+	if host := u.Hostname(); host != "" {
+		params["host"] = host
+	}
+	if port := u.Port(); port != "" {
+		params["port"] = port
+	}
+	if dbname := strings.TrimPrefix(u.Path, "/"); dbname != "" {
+		params["dbname"] = dbname
+	}
+
+	// Query parameters override the URI components, as in libpq
+	for key, values := range u.Query() {
+		if len(values) > 0 {
+			params[key] = values[len(values)-1]
+		}
+	}
+	return params, nil
+}
+
+// parseDSN extracts connection parameters from a key=value connection string
+func parseDSN(s string) (map[string]string, error) {
+	params := make(map[string]string)
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); {
+		// Skip whitespace between pairs
+		for i < len(runes) && runes[i] == ' ' {
+			i++
+		}
+		if i >= len(runes) {
+			break
+		}
+
+		start := i
+		for i < len(runes) && runes[i] != '=' && runes[i] != ' ' {
+			i++
+		}
+		key := string(runes[start:i])
+
+		for i < len(runes) && runes[i] == ' ' {
+			i++
+		}
+		if i >= len(runes) || runes[i] != '=' {
+			return nil, fmt.Errorf("missing \"=\" after %q in connection string", key)
+		}
+		i++
+		for i < len(runes) && runes[i] == ' ' {
+			i++
+		}
+
+		var value strings.Builder
+		if i < len(runes) && runes[i] == '\'' {
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					value.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '\'' {
+					closed = true
+					i++
+					break
+				}
+				value.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated quoted value for %q in connection string", key)
+			}
+		} else {
+			for i < len(runes) && runes[i] != ' ' {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				value.WriteRune(runes[i])
+				i++
+			}
+		}
+
+		params[key] = value.String()
+	}
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("no parameters found in connection string")
+	}
+	return params, nil
+}
@@ -1,71 +1,430 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lib/pq"
 )
 
-// Service handles database operations
-type Service struct{}
+// ErrNotConnected is returned when a query is made before Connect
+var ErrNotConnected = errors.New("not connected to a database server")
+
+// Connection pool limits for each database
+const (
+	maxOpenConns    = 4
+	maxIdleConns    = 2
+	connMaxIdleTime = 5 * time.Minute
+)
+
+// Service handles database operations over long-lived connection pools
+type Service struct {
+	logger *slog.Logger
+
+	mu    sync.Mutex
+	conn  types.DatabaseConnection
+	pools map[string]*sql.DB // one pool per database of the active server
+}
 
 // NewService creates a new database service
-func NewService() *Service {
-	return &Service{}
+func NewService(logger *slog.Logger) *Service {
+	return &Service{logger: logger, pools: make(map[string]*sql.DB)}
+}
+
+// Connect makes conn the active server, replacing the pools of any previous one
+func (s *Service) Connect(ctx context.Context, conn types.DatabaseConnection) error {
+	db, err := s.open(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	old := s.pools
+	s.conn = conn
+	s.pools = map[string]*sql.DB{conn.Database: db}
+	s.mu.Unlock()
+
+	for _, pool := range old {
+		pool.Close()
+	}
+	return nil
 }
 
-// ListDatabases retrieves all non-template databases from PostgreSQL
-func (s *Service) ListDatabases(host, port, user, password, dbname string) ([]string, error) {
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		host, port, user, password, dbname)
+// Close closes every open pool
+func (s *Service) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	db, err := sql.Open("postgres", connStr)
+	var errs []error
+	for name, pool := range s.pools {
+		if err := pool.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close pool for %s: %w", name, err))
+		}
+	}
+	s.pools = make(map[string]*sql.DB)
+	return errors.Join(errs...)
+}
+
+// Connection returns the parameters of the active server
+func (s *Service) Connection() types.DatabaseConnection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn
+}
+
+// DB returns the pool for dbname on the active server, opening it on first use. A cached pool
+// is returned as is: database/sql replaces broken connections on its own, and the pool may be
+// in use by other goroutines.
+func (s *Service) DB(ctx context.Context, dbname string) (*sql.DB, error) {
+	s.mu.Lock()
+	if s.conn.Host == "" {
+		s.mu.Unlock()
+		return nil, ErrNotConnected
+	}
+	if dbname == "" {
+		dbname = s.conn.Database
+	}
+	pool, ok := s.pools[dbname]
+	server := s.conn
+	s.mu.Unlock()
+	if ok {
+		return pool, nil
+	}
+
+	conn := server
+	conn.Database = dbname
+	pool, err := s.open(ctx, conn)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database connection: %w", err)
+		return nil, err
 	}
-	defer db.Close()
 
-	if err = db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	// Another caller may have opened the same pool, or Connect switched servers, meanwhile
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != server {
+		pool.Close()
+		return nil, fmt.Errorf("active server changed while connecting to %s", dbname)
+	}
+	if existing, ok := s.pools[dbname]; ok {
+		pool.Close()
+		return existing, nil
+	}
+	s.pools[dbname] = pool
+	return pool, nil
+}
+
+// listDatabasesQuery returns non-template databases with size, owner, encoding and active connections
+const listDatabasesQuery = `
+SELECT d.datname,
+       CASE WHEN has_database_privilege(d.datname, 'CONNECT') THEN pg_database_size(d.datname) END,
+       pg_get_userbyid(d.datdba),
+       pg_encoding_to_char(d.encoding),
+       COALESCE(s.numbackends, 0)
+FROM pg_database d
+LEFT JOIN pg_stat_database s ON s.datid = d.oid
+WHERE d.datistemplate = false
+ORDER BY d.datname`
+
+// ListDatabases retrieves all non-template databases and their metadata from the active server
+func (s *Service) ListDatabases(ctx context.Context) ([]types.DatabaseInfo, error) {
+	db, err := s.DB(ctx, "")
+	if err != nil {
+		return nil, err
 	}
 
-	rows, err := db.Query("SELECT datname FROM pg_database WHERE datistemplate = false ORDER BY datname")
+	rows, err := db.QueryContext(ctx, listDatabasesQuery)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query databases: %w", err)
 	}
 	defer rows.Close()
 
-	var databases []string
+	var databases []types.DatabaseInfo
 	for rows.Next() {
-		var dbName string
-		if err := rows.Scan(&dbName); err != nil {
-			return nil, fmt.Errorf("failed to scan database name: %w", err)
+		var info types.DatabaseInfo
+		var size sql.NullInt64
+		if err := rows.Scan(&info.Name, &size, &info.Owner, &info.Encoding, &info.Connections); err != nil {
+			return nil, fmt.Errorf("failed to scan database: %w", err)
 		}
-		databases = append(databases, dbName)
+		info.Size = -1
+		if size.Valid {
+			info.Size = size.Int64
+		}
+		databases = append(databases, info)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating over rows: %w", err)
 	}
 
+	s.logger.Debug("listed databases", "count", len(databases))
 	return databases, nil
 }
 
-// TestConnection tests the database connection
-func (s *Service) TestConnection(host, port, user, password, dbname string) error {
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		host, port, user, password, dbname)
+// listTablesQuery returns tables, views and foreign tables outside system schemas with row estimates and sizes
+const listTablesQuery = `
+SELECT n.nspname,
+       c.relname,
+       c.relkind::text,
+       CASE WHEN c.reltuples < 0 OR c.relkind IN ('v', 'f') THEN -1 ELSE c.reltuples::int8 END,
+       CASE WHEN c.relkind IN ('r', 'p', 'm') THEN pg_total_relation_size(c.oid) ELSE -1 END
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('r', 'p', 'v', 'm', 'f')
+  AND NOT c.relispartition
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg\_toast%'
+  AND n.nspname NOT LIKE 'pg\_temp%'
+ORDER BY n.nspname, c.relname`
+
+// ListTables retrieves the tables and views of dbname on the active server
+func (s *Service) ListTables(ctx context.Context, dbname string) ([]types.TableInfo, error) {
+	db, err := s.DB(ctx, dbname)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, listTablesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []types.TableInfo
+	for rows.Next() {
+		var t types.TableInfo
+		if err := rows.Scan(&t.Schema, &t.Name, &t.Kind, &t.Rows, &t.Size); err != nil {
+			return nil, fmt.Errorf("failed to scan table: %w", err)
+		}
+		tables = append(tables, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	s.logger.Debug("listed tables", "database", dbname, "count", len(tables))
+	return tables, nil
+}
+
+// ListTablesCmd creates a command that lists the tables of dbname in the background
+func (s *Service) ListTablesCmd(dbname string) tea.Cmd {
+	return func() tea.Msg {
+		tables, err := s.ListTables(context.Background(), dbname)
+		return types.TablesLoadedMsg{Database: dbname, Tables: tables, Err: err}
+	}
+}
+
+// listSchemasQuery returns the user schemas with their table count and the size of their tables
+// and materialized views, indexes and TOAST included
+const listSchemasQuery = `
+SELECT n.nspname,
+       pg_get_userbyid(n.nspowner),
+       count(c.oid) FILTER (WHERE c.relkind IN ('r', 'p') AND NOT c.relispartition),
+       coalesce(sum(pg_total_relation_size(c.oid)) FILTER (WHERE c.relkind IN ('r', 'm')), 0)::int8
+FROM pg_namespace n
+LEFT JOIN pg_class c ON c.relnamespace = n.oid
+WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg\_toast%'
+  AND n.nspname NOT LIKE 'pg\_temp%'
+GROUP BY n.oid, n.nspname, n.nspowner
+ORDER BY n.nspname`
+
+// ListSchemas retrieves the user schemas of dbname on the active server
+func (s *Service) ListSchemas(ctx context.Context, dbname string) ([]types.SchemaInfo, error) {
+	db, err := s.DB(ctx, dbname)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, listSchemasQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schemas: %w", err)
+	}
+	defer rows.Close()
+
+	var schemas []types.SchemaInfo
+	for rows.Next() {
+		var sc types.SchemaInfo
+		if err := rows.Scan(&sc.Name, &sc.Owner, &sc.Tables, &sc.Size); err != nil {
+			return nil, fmt.Errorf("failed to scan schema: %w", err)
+		}
+		schemas = append(schemas, sc)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	s.logger.Debug("listed schemas", "database", dbname, "count", len(schemas))
+	return schemas, nil
+}
+
+// ListSchemasCmd creates a command that lists the schemas of dbname in the background
+func (s *Service) ListSchemasCmd(dbname string) tea.Cmd {
+	return func() tea.Msg {
+		schemas, err := s.ListSchemas(context.Background(), dbname)
+		return types.SchemasLoadedMsg{Database: dbname, Schemas: schemas, Err: err}
+	}
+}
 
-	db, err := sql.Open("postgres", connStr)
+// ServerVersion returns the active server's version in server_version_num format, e.g. 160002
+func (s *Service) ServerVersion(ctx context.Context) (int, error) {
+	db, err := s.DB(ctx, "")
 	if err != nil {
-		return fmt.Errorf("failed to open database connection: %w", err)
+		return 0, err
+	}
+
+	var version int
+	if err := db.QueryRowContext(ctx, "SHOW server_version_num").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to query server version: %w", err)
 	}
-	defer db.Close()
+	return version, nil
+}
+
+// databaseStatusQuery returns the sessions connected to a database, or no row when it does not exist
+const databaseStatusQuery = `
+SELECT (SELECT count(*) FROM pg_stat_activity a WHERE a.datname = d.datname AND a.pid <> pg_backend_pid())
+FROM pg_database d
+WHERE d.datname = $1`
 
-	if err = db.Ping(); err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+// DatabaseStatus reports whether dbname exists on the active server and how many other sessions use it
+func (s *Service) DatabaseStatus(ctx context.Context, dbname string) (bool, int, error) {
+	db, err := s.DB(ctx, "")
+	if err != nil {
+		return false, 0, err
 	}
 
+	var connections int
+	err = db.QueryRowContext(ctx, databaseStatusQuery, dbname).Scan(&connections)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, 0, nil
+	}
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to query database %s: %w", dbname, err)
+	}
+	return true, connections, nil
+}
+
+// DatabaseStatusCmd creates a command that checks dbname in the background
+func (s *Service) DatabaseStatusCmd(dbname string) tea.Cmd {
+	return func() tea.Msg {
+		exists, connections, err := s.DatabaseStatus(context.Background(), dbname)
+		return types.DatabaseStatusMsg{Database: dbname, Exists: exists, Connections: connections, Err: err}
+	}
+}
+
+// CreateDatabase creates an empty database named dbname on the active server
+func (s *Service) CreateDatabase(ctx context.Context, dbname string) error {
+	db, err := s.DB(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.ExecContext(ctx, "CREATE DATABASE "+pq.QuoteIdentifier(dbname)); err != nil {
+		return fmt.Errorf("failed to create database %s: %w", dbname, err)
+	}
+	s.logger.Info("created database", "database", dbname)
 	return nil
 }
+
+// DropDatabase drops dbname on the active server
+func (s *Service) DropDatabase(ctx context.Context, dbname string) error {
+	db, err := s.DB(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.ExecContext(ctx, "DROP DATABASE "+pq.QuoteIdentifier(dbname)); err != nil {
+		return fmt.Errorf("failed to drop database %s: %w", dbname, err)
+	}
+	s.logger.Info("dropped database", "database", dbname)
+	return nil
+}
+
+// ConnectCmd creates a command that connects to conn and lists its databases in the background
+func (s *Service) ConnectCmd(ctx context.Context, attempt int, conn types.DatabaseConnection) tea.Cmd {
+	return func() tea.Msg {
+		var databases []types.DatabaseInfo
+		var version int
+		err := s.Connect(ctx, conn)
+		if err == nil {
+			version, err = s.ServerVersion(ctx)
+		}
+		if err == nil {
+			databases, err = s.ListDatabases(ctx)
+		}
+		if ctx.Err() == context.Canceled {
+			err = ctx.Err()
+		}
+		return types.DatabasesLoadedMsg{Attempt: attempt, Databases: databases, TunnelPort: conn.TunnelPort,
+			ServerVersion: version, Err: err}
+	}
+}
+
+// TestConnection tests the database connection without changing the active server
+func (s *Service) TestConnection(ctx context.Context, conn types.DatabaseConnection) error {
+	db, err := s.open(ctx, conn)
+	if err != nil {
+		return err
+	}
+	return db.Close()
+}
+
+// open opens and pings a connection pool, logging the attempt
+func (s *Service) open(ctx context.Context, conn types.DatabaseConnection) (*sql.DB, error) {
+	logger := s.logger.With("host", conn.Host, "port", conn.Port, "user", conn.User, "database", conn.Database)
+	logger.Info("connecting to database", "timeout", conn.ConnectTimeout)
+	start := time.Now()
+
+	db, err := sql.Open("postgres", connString(conn))
+	if err != nil {
+		logger.Error("failed to open database connection", "error", err)
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxIdleConns)
+	db.SetConnMaxIdleTime(connMaxIdleTime)
+
+	if err = db.PingContext(ctx); err != nil {
+		db.Close()
+		logger.Error("connection failed", "duration", time.Since(start), "error", err)
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	logger.Info("connected to database", "duration", time.Since(start))
+	return db, nil
+}
+
+// connString builds a libpq key=value connection string with quoted values
+func connString(conn types.DatabaseConnection) string {
+	host, port := conn.Address()
+	params := []string{
+		"host=" + quote(host),
+		"port=" + quote(port),
+		"user=" + quote(conn.User),
+		"password=" + quote(conn.Password),
+		"dbname=" + quote(conn.Database),
+	}
+	sslMode := conn.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	params = append(params, "sslmode="+quote(sslMode))
+	if conn.ConnectTimeout > 0 {
+		params = append(params, fmt.Sprintf("connect_timeout=%d", max(1, int(conn.ConnectTimeout.Seconds()))))
+	}
+	return strings.Join(params, " ")
+}
+
+// quote escapes a connection string value
+func quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
@@ -0,0 +1,38 @@
+package export
+
+import (
+	"bufio"
+	"database/sql"
+	"strings"
+)
+
+// writeCSVRecord writes one line in PostgreSQL's COPY CSV format: NULL is an empty unquoted
+// field and empty strings are quoted, so files load back with COPY ... (FORMAT csv, HEADER)
+func writeCSVRecord(w *bufio.Writer, values []sql.NullString) error {
+	for i, v := range values {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		if !v.Valid {
+			continue
+		}
+		if v.String == "" || v.String == `\.` || strings.ContainsAny(v.String, ",\"\r\n") {
+			w.WriteByte('"')
+			w.WriteString(strings.ReplaceAll(v.String, `"`, `""`))
+			w.WriteByte('"')
+			continue
+		}
+		w.WriteString(v.String)
+	}
+	_, err := w.WriteString("\n")
+	return err
+}
+
+// csvHeader returns the column names as a CSV record
+func csvHeader(columns []Column) []sql.NullString {
+	header := make([]sql.NullString, len(columns))
+	for i, c := range columns {
+		header[i] = sql.NullString{String: c.Name, Valid: true}
+	}
+	return header
+}
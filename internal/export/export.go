@@ -0,0 +1,340 @@
+package export
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/catalog"
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/database"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ManifestFormat identifies the layout of export archives
+const ManifestFormat = "snaptui-export/1"
+
+// Manifest describes the contents of an export archive
+type Manifest struct {
+	Format        string      `json:"format"`
+	Database      string      `json:"database"`
+	Host          string      `json:"host"`
+	Port          string      `json:"port"`
+	ServerVersion int         `json:"server_version,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	Schema        string      `json:"schema"`
+	Tables        []TableFile `json:"tables"`
+
+	// Masking names the masking profile applied, reported per column in MaskedColumns
+	Masking       string         `json:"masking,omitempty"`
+	MaskedColumns []MaskedColumn `json:"masked_columns,omitempty"`
+}
+
+// TableFile describes the data file of one table in the archive
+type TableFile struct {
+	Table  string `json:"table"`
+	File   string `json:"file"`
+	Rows   int64  `json:"rows"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Service exports databases over the SQL connection, without pg_dump
+type Service struct {
+	logger  *slog.Logger
+	db      *database.Service
+	catalog *catalog.Service
+}
+
+// NewService creates a new export service reading through db and recording runs in catalog
+func NewService(logger *slog.Logger, db *database.Service, catalog *catalog.Service) *Service {
+	return &Service{logger: logger, db: db, catalog: catalog}
+}
+
+// ExportDatabase writes every table of dbname as CSV, plus schema.json and manifest.json,
+// into a single .tar.gz archive in dir and returns its path. All tables are read from one
+// repeatable-read snapshot. A non-nil masking profile is applied to the table data as it is
+// written, and a text report of the masked columns is written next to the archive.
+func (s *Service) ExportDatabase(ctx context.Context, conn types.DatabaseConnection, dbname, dir string, masking *config.MaskingProfile) (string, error) {
+	logger := s.logger.With("host", conn.Host, "database", dbname)
+	start := time.Now()
+
+	db, err := s.db.DB(ctx, dbname)
+	if err != nil {
+		return "", err
+	}
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return "", fmt.Errorf("failed to start export transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	tables, err := describeTables(ctx, tx)
+	if err != nil {
+		return "", err
+	}
+
+	// Masks are planned up front, so an unsuitable rule fails before any data is read
+	masks := make([][]columnMask, len(tables))
+	var reports []*MaskedColumn
+	if masking != nil {
+		for i, t := range tables {
+			tableMasks, tableReports, err := planMasks(*masking, t)
+			if err != nil {
+				return "", fmt.Errorf("masking profile %q: %w", masking.Name, err)
+			}
+			masks[i] = tableMasks
+			reports = append(reports, tableReports...)
+		}
+	}
+
+	// Table data is staged next to the archive, since tar headers need each file's size
+	staging, err := os.MkdirTemp(dir, ".snaptui-export-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	manifest := Manifest{
+		Format:        ManifestFormat,
+		Database:      dbname,
+		Host:          conn.Host,
+		Port:          conn.Port,
+		ServerVersion: conn.ServerVersion,
+		CreatedAt:     start,
+		Schema:        "schema.json",
+	}
+	for i, t := range tables {
+		file := "data/" + strings.ReplaceAll(t.QualifiedName(), "/", "_") + ".csv"
+		rows, err := exportTable(ctx, tx, t, filepath.Join(staging, filepath.FromSlash(file)), masks[i])
+		if err != nil {
+			logger.Error("table export failed", "table", t.QualifiedName(), "error", err)
+			return "", fmt.Errorf("failed to export %s: %w", t.QualifiedName(), err)
+		}
+		manifest.Tables = append(manifest.Tables, TableFile{Table: t.QualifiedName(), File: file, Rows: rows})
+		logger.Debug("exported table", "table", t.QualifiedName(), "rows", rows)
+	}
+
+	suffix := "export"
+	if masking != nil {
+		suffix = "masked.export"
+		manifest.Masking = masking.Name
+		for _, r := range reports {
+			manifest.MaskedColumns = append(manifest.MaskedColumns, *r)
+		}
+	}
+
+	filename := fmt.Sprintf("%s_%s.%s.tar.gz", dbname, start.Format("20060102_150405"), suffix)
+	path := filepath.Join(dir, filename)
+	if err := writeArchive(path, staging, &manifest, tables); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	if masking != nil {
+		if err := writeMaskingReport(MaskingReportPath(path), &manifest); err != nil {
+			logger.Warn("failed to write masking report", "error", err)
+		}
+		logger.Info("masked export", "profile", masking.Name, "columns", len(manifest.MaskedColumns))
+	}
+
+	logger.Info("logical export finished", "tables", len(tables), "duration", time.Since(start), "file", path)
+	return path, nil
+}
+
+// exportTable writes the rows of t as CSV with a header line and returns the row count
+func exportTable(ctx context.Context, tx *sql.Tx, t Table, path string, masks []columnMask) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	buf := bufio.NewWriter(f)
+
+	var w rowWriter
+	w, err = newCSVWriter(buf, t.Columns)
+	if err != nil {
+		return 0, err
+	}
+	if len(masks) > 0 {
+		w = &maskingWriter{w: w, masks: masks}
+	}
+	count, err := copyRows(ctx, tx, selectQuery(t, TableOptions{Format: FormatCSV}, true), w, len(t.Columns))
+	if err != nil {
+		return count, err
+	}
+	if err := buf.Flush(); err != nil {
+		return count, err
+	}
+	return count, f.Close()
+}
+
+// writeArchive packages manifest.json, schema.json and the staged table files into a .tar.gz
+func writeArchive(path, staging string, manifest *Manifest, tables []Table) error {
+	// Checksums and sizes go into the manifest, which is written first
+	for i, tf := range manifest.Tables {
+		size, sum, err := fileDigest(filepath.Join(staging, filepath.FromSlash(tf.File)))
+		if err != nil {
+			return err
+		}
+		manifest.Tables[i].Size, manifest.Tables[i].SHA256 = size, sum
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create export archive: %w", err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	if err := addJSON(tw, "manifest.json", manifest, manifest.CreatedAt); err != nil {
+		return err
+	}
+	if err := addJSON(tw, manifest.Schema, tables, manifest.CreatedAt); err != nil {
+		return err
+	}
+	for _, tf := range manifest.Tables {
+		if err := addFile(tw, tf.File, filepath.Join(staging, filepath.FromSlash(tf.File)), manifest.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish export archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finish export archive: %w", err)
+	}
+	return f.Close()
+}
+
+// addJSON writes v as an indented JSON file in the archive
+func addJSON(tw *tar.Writer, name string, v any, modTime time.Time) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	header := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: modTime}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	_, err = tw.Write(data)
+	return err
+}
+
+// addFile copies a staged file into the archive
+func addFile(tw *tar.Writer, name, path string, modTime time.Time) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	header := &tar.Header{Name: name, Mode: 0o644, Size: info.Size(), ModTime: modTime}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// fileDigest returns the size and SHA-256 of a file
+func fileDigest(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// PerformExportCmd creates a command that exports the selected databases into dir,
+// applying masking when it is not nil
+func (s *Service) PerformExportCmd(m types.Model, dir string, masking *config.MaskingProfile) tea.Cmd {
+	return func() tea.Msg {
+		startedAt := time.Now()
+		conn := m.Connection()
+
+		var errors []string
+		var filenames []string
+		successCount := 0
+		for i, db := range m.Choices {
+			if i == 0 { // Skip "All Databases"
+				continue
+			}
+			entry := catalog.Entry{
+				Profile:    m.ProfileName,
+				Host:       conn.Host,
+				Port:       conn.Port,
+				Database:   db,
+				Kind:       catalog.KindExport,
+				SourceSize: m.DatabaseInfo[db].Size,
+				StartedAt:  time.Now(),
+			}
+
+			path, err := s.ExportDatabase(context.Background(), conn, db, dir, masking)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("Error exporting %s: %v", db, err))
+				entry.Error = err.Error()
+			} else {
+				successCount++
+				filenames = append(filenames, filepath.Base(path))
+				if masking != nil {
+					filenames = append(filenames, filepath.Base(MaskingReportPath(path)))
+				}
+				entry.Path = path
+				entry.Success = true
+			}
+			s.record(entry)
+		}
+
+		s.logger.Info("export run finished", "success", successCount, "errors", len(errors), "duration", time.Since(startedAt))
+		return types.BackupCompleteMsg{
+			Success:    successCount,
+			Errors:     errors,
+			Filenames:  filenames,
+			StartedAt:  startedAt,
+			FinishedAt: time.Now(),
+		}
+	}
+}
+
+// record stores a finished export in the catalog
+func (s *Service) record(entry catalog.Entry) {
+	entry.FinishedAt = time.Now()
+	if entry.Path != "" {
+		if info, err := os.Stat(entry.Path); err == nil {
+			entry.OutputSize = info.Size()
+		}
+	}
+
+	if err := s.catalog.Record(entry); err != nil {
+		s.logger.Error("failed to record export in catalog", "database", entry.Database, "error", err)
+	}
+}
@@ -0,0 +1,255 @@
+package export
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+)
+
+// MaskedColumn reports a column masked during an export
+type MaskedColumn struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	Action string `json:"action"`
+	Values int64  `json:"values"` // non-NULL values replaced
+}
+
+// columnMask replaces the values of one column
+type columnMask struct {
+	index  int
+	name   string
+	apply  func(string) sql.NullString
+	report *MaskedColumn
+}
+
+// maskingWriter applies column masks to each row before handing it to w
+type maskingWriter struct {
+	w     rowWriter
+	masks []columnMask
+}
+
+func (m *maskingWriter) WriteRow(values []sql.NullString) error {
+	for _, mask := range m.masks {
+		if !values[mask.index].Valid {
+			continue
+		}
+		values[mask.index] = mask.apply(values[mask.index].String)
+		mask.report.Values++
+	}
+	return m.w.WriteRow(values)
+}
+
+func (m *maskingWriter) Close() error {
+	return m.w.Close()
+}
+
+// jsonMaskingWriter applies column masks to the row_to_json objects of a JSON Lines export,
+// keeping the other fields and their order as the server wrote them
+type jsonMaskingWriter struct {
+	w     rowWriter
+	masks map[string]columnMask
+}
+
+func newJSONMaskingWriter(w rowWriter, masks []columnMask) *jsonMaskingWriter {
+	m := &jsonMaskingWriter{w: w, masks: make(map[string]columnMask, len(masks))}
+	for _, mask := range masks {
+		m.masks[mask.name] = mask
+	}
+	return m
+}
+
+func (m *jsonMaskingWriter) WriteRow(values []sql.NullString) error {
+	masked, err := m.maskObject(values[0].String)
+	if err != nil {
+		return fmt.Errorf("failed to mask row: %w", err)
+	}
+	values[0].String = masked
+	return m.w.WriteRow(values)
+}
+
+func (m *jsonMaskingWriter) Close() error {
+	return m.w.Close()
+}
+
+// maskObject rewrites the masked fields of one JSON object. Masks other than null and fixed
+// only apply to text columns, whose values are JSON strings holding the column text.
+func (m *jsonMaskingWriter) maskObject(object string) (string, error) {
+	dec := json.NewDecoder(strings.NewReader(object))
+	if _, err := dec.Token(); err != nil { // {
+		return "", err
+	}
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		key, _ := token.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return "", err
+		}
+
+		if mask, ok := m.masks[key]; ok && string(raw) != "null" {
+			var text string
+			if err := json.Unmarshal(raw, &text); err != nil {
+				// Numbers, booleans and nested values are masked from their JSON text
+				text = string(raw)
+			}
+			raw = json.RawMessage("null")
+			if v := mask.apply(text); v.Valid {
+				raw = jsonString(v.String)
+			}
+			mask.report.Values++
+		}
+
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		b.Write(jsonString(key))
+		b.WriteByte(':')
+		b.Write(raw)
+	}
+	b.WriteByte('}')
+	return b.String(), nil
+}
+
+// jsonString encodes s as a JSON string without escaping HTML characters
+func jsonString(s string) []byte {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n"))
+}
+
+// textTypes are the column types that can hold the output of hash, email and redact
+var textTypes = []string{"text", "character varying", "character", "citext", "name"}
+
+// planMasks builds the masks profile applies to the columns of t, one report per masked column
+func planMasks(profile config.MaskingProfile, t Table) ([]columnMask, []*MaskedColumn, error) {
+	var masks []columnMask
+	var reports []*MaskedColumn
+	for i, c := range t.Columns {
+		rule, ok := profile.Match(t.Schema, t.Name, c.Name)
+		if !ok {
+			continue
+		}
+		if rule.Action == config.MaskNull && c.NotNull {
+			return nil, nil, fmt.Errorf("column %s.%s is NOT NULL and cannot be masked with null", t.QualifiedName(), c.Name)
+		}
+		if rule.Action != config.MaskNull && rule.Action != config.MaskFixed && !isTextType(c.Type) {
+			return nil, nil, fmt.Errorf("column %s.%s has type %s; %s only applies to text columns, use null or fixed",
+				t.QualifiedName(), c.Name, c.Type, rule.Action)
+		}
+
+		apply := maskFunc(rule, profile.Salt)
+		// A replacement longer than the column's declared length would not load back
+		if limit := typeLength(c.Type); limit > 0 {
+			unlimited := apply
+			apply = func(v string) sql.NullString { return truncate(unlimited(v), limit) }
+		}
+		report := &MaskedColumn{Table: t.QualifiedName(), Column: c.Name, Action: rule.Action}
+		masks = append(masks, columnMask{index: i, name: c.Name, apply: apply, report: report})
+		reports = append(reports, report)
+	}
+	return masks, reports, nil
+}
+
+// isTextType reports whether a format_type name is a character type
+func isTextType(typ string) bool {
+	for _, t := range textTypes {
+		if typ == t || strings.HasPrefix(typ, t+"(") {
+			return true
+		}
+	}
+	return false
+}
+
+// lengthType matches the length modifier of a bounded character type, e.g. character varying(20)
+var lengthType = regexp.MustCompile(`^(?:character varying|character)\((\d+)\)$`)
+
+// typeLength returns the declared length of a character(n) or character varying(n) column, 0 when unbounded
+func typeLength(typ string) int {
+	match := lengthType.FindStringSubmatch(typ)
+	if match == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(match[1])
+	return n
+}
+
+// truncate cuts a valid value to at most limit characters
+func truncate(v sql.NullString, limit int) sql.NullString {
+	if runes := []rune(v.String); v.Valid && len(runes) > limit {
+		v.String = string(runes[:limit])
+	}
+	return v
+}
+
+// maskFunc returns the replacement function of rule. Hash and email are deterministic for a
+// given salt, so masked keys still join across tables.
+func maskFunc(rule config.MaskRule, salt string) func(string) sql.NullString {
+	switch rule.Action {
+	case config.MaskNull:
+		return func(string) sql.NullString { return sql.NullString{} }
+	case config.MaskHash:
+		return func(v string) sql.NullString { return valid(digest(salt, v)) }
+	case config.MaskEmail:
+		return func(v string) sql.NullString { return valid("user_" + digest(salt, v)[:12] + "@example.com") }
+	case config.MaskRedact:
+		return func(v string) sql.NullString { return valid(redact(v, rule.Keep)) }
+	default:
+		return func(string) sql.NullString { return valid(rule.Value) }
+	}
+}
+
+// digest returns the hex HMAC-SHA256 of v keyed with salt
+func digest(salt, v string) string {
+	h := hmac.New(sha256.New, []byte(salt))
+	h.Write([]byte(v))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// redact replaces every character of v but the last keep with '*'
+func redact(v string, keep int) string {
+	runes := []rune(v)
+	hidden := max(len(runes)-keep, 0)
+	return strings.Repeat("*", hidden) + string(runes[hidden:])
+}
+
+func valid(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: true}
+}
+
+// MaskingReportPath returns the path of the masking report written next to an export archive
+func MaskingReportPath(archive string) string {
+	return strings.TrimSuffix(archive, ".tar.gz") + ".masking.txt"
+}
+
+// writeMaskingReport writes a plain-text summary of the masked columns of manifest
+func writeMaskingReport(path string, manifest *Manifest) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Database: %s\n", manifest.Database)
+	fmt.Fprintf(&b, "Exported at: %s\n", manifest.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Masking profile: %s\n\n", manifest.Masking)
+	if len(manifest.MaskedColumns) == 0 {
+		b.WriteString("No column matched the masking rules.\n")
+	}
+	for _, c := range manifest.MaskedColumns {
+		fmt.Fprintf(&b, "%s.%s\t%s\t%d values\n", c.Table, c.Column, c.Action, c.Values)
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
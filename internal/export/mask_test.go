@@ -0,0 +1,254 @@
+package export
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+)
+
+func TestDigestDeterministic(t *testing.T) {
+	a := digest("salt", "ana@exemplo.com")
+	if b := digest("salt", "ana@exemplo.com"); a != b {
+		t.Errorf("digest is not deterministic: %q != %q", a, b)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{64}$`).MatchString(a) {
+		t.Errorf("digest = %q, want 64 hex characters", a)
+	}
+	if b := digest("other", "ana@exemplo.com"); a == b {
+		t.Error("digest does not depend on the salt")
+	}
+	if b := digest("salt", "bia@exemplo.com"); a == b {
+		t.Error("digest does not depend on the value")
+	}
+	// Well-known HMAC-SHA256 example, so the digest stays compatible across versions
+	if got, want := digest("key", "The quick brown fox jumps over the lazy dog"), "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"; got != want {
+		t.Errorf("digest = %q, want %q", got, want)
+	}
+}
+
+func TestMaskFunc(t *testing.T) {
+	const salt = "s3cr3t"
+	tests := []struct {
+		name  string
+		rule  config.MaskRule
+		value string
+		want  sql.NullString
+	}{
+		{"null", config.MaskRule{Action: config.MaskNull}, "ana", sql.NullString{}},
+		{"hash", config.MaskRule{Action: config.MaskHash}, "ana", valid(digest(salt, "ana"))},
+		{"email", config.MaskRule{Action: config.MaskEmail}, "ana@exemplo.com", valid("user_" + digest(salt, "ana@exemplo.com")[:12] + "@example.com")},
+		{"redact keeps the last characters", config.MaskRule{Action: config.MaskRedact, Keep: 4}, "4111111111111111", valid("************1111")},
+		{"redact without keep", config.MaskRule{Action: config.MaskRedact}, "ação", valid("****")},
+		{"redact keep longer than the value", config.MaskRule{Action: config.MaskRedact, Keep: 10}, "abc", valid("abc")},
+		{"fixed", config.MaskRule{Action: config.MaskFixed, Value: "REMOVIDO"}, "ana", valid("REMOVIDO")},
+		{"fixed empty", config.MaskRule{Action: config.MaskFixed}, "ana", valid("")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apply := maskFunc(tt.rule, salt)
+			got := apply(tt.value)
+			if got != tt.want {
+				t.Errorf("mask(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+			if again := apply(tt.value); again != got {
+				t.Errorf("mask(%q) is not deterministic: %+v != %+v", tt.value, again, got)
+			}
+		})
+	}
+}
+
+func TestMaskEmailFormat(t *testing.T) {
+	got := maskFunc(config.MaskRule{Action: config.MaskEmail}, "salt")("ana@exemplo.com").String
+	if !regexp.MustCompile(`^user_[0-9a-f]{12}@example\.com$`).MatchString(got) {
+		t.Errorf("email mask = %q, want user_<12 hex>@example.com", got)
+	}
+}
+
+func TestPlanMasks(t *testing.T) {
+	profile := config.MaskingProfile{
+		Name: "dev",
+		Salt: "salt",
+		Rules: []config.MaskRule{
+			{Table: "public.customers", Column: "email", Action: config.MaskEmail},
+			{Table: "customers", Column: "doc", Action: config.MaskRedact, Keep: 2},
+			{Table: "*", Column: "phone", Action: config.MaskNull},
+		},
+	}
+	table := Table{Schema: "public", Name: "customers", Columns: []Column{
+		{Name: "id", Type: "integer", NotNull: true},
+		{Name: "email", Type: "character varying(20)"},
+		{Name: "doc", Type: "character(5)"},
+		{Name: "phone", Type: "text"},
+	}}
+
+	masks, reports, err := planMasks(profile, table)
+	if err != nil {
+		t.Fatalf("planMasks: %v", err)
+	}
+	if len(masks) != 3 || len(reports) != 3 {
+		t.Fatalf("planned %d masks and %d reports, want 3", len(masks), len(reports))
+	}
+	wantIndex := []int{1, 2, 3}
+	wantAction := []string{config.MaskEmail, config.MaskRedact, config.MaskNull}
+	for i, mask := range masks {
+		if mask.index != wantIndex[i] || mask.report.Action != wantAction[i] || mask.report.Table != "public.customers" {
+			t.Errorf("mask %d = index %d, %+v", i, mask.index, *mask.report)
+		}
+	}
+
+	// The email replacement is 33 characters long and is cut to the varchar(20) length
+	if got := masks[0].apply("ana@exemplo.com"); !got.Valid || len(got.String) != 20 || !strings.HasPrefix(got.String, "user_") {
+		t.Errorf("email mask on varchar(20) = %+v, want 20 characters", got)
+	}
+	if got := masks[1].apply("1234567"); got.String != "*****" {
+		t.Errorf("redact mask on character(5) = %q, want %q", got.String, "*****")
+	}
+	if got := masks[2].apply("555-1234"); got.Valid {
+		t.Errorf("null mask = %+v, want NULL", got)
+	}
+}
+
+func TestPlanMasksErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   config.MaskRule
+		column Column
+		err    string
+	}{
+		{"null on not null column", config.MaskRule{Action: config.MaskNull}, Column{Name: "c", Type: "text", NotNull: true}, "is NOT NULL"},
+		{"hash on integer", config.MaskRule{Action: config.MaskHash}, Column{Name: "c", Type: "integer"}, "only applies to text columns"},
+		{"email on jsonb", config.MaskRule{Action: config.MaskEmail}, Column{Name: "c", Type: "jsonb"}, "only applies to text columns"},
+		{"redact on date", config.MaskRule{Action: config.MaskRedact}, Column{Name: "c", Type: "date"}, "only applies to text columns"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.Table, tt.rule.Column = "t", "c"
+			profile := config.MaskingProfile{Name: "dev", Rules: []config.MaskRule{tt.rule}}
+			_, _, err := planMasks(profile, Table{Schema: "public", Name: "t", Columns: []Column{tt.column}})
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("planMasks error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+
+	// null and fixed apply to any type
+	profile := config.MaskingProfile{Name: "dev", Rules: []config.MaskRule{
+		{Table: "t", Column: "a", Action: config.MaskNull},
+		{Table: "t", Column: "b", Action: config.MaskFixed, Value: "0"},
+	}}
+	table := Table{Schema: "public", Name: "t", Columns: []Column{{Name: "a", Type: "integer"}, {Name: "b", Type: "numeric", NotNull: true}}}
+	if _, _, err := planMasks(profile, table); err != nil {
+		t.Errorf("planMasks with null and fixed on non-text columns: %v", err)
+	}
+}
+
+func TestTypeLength(t *testing.T) {
+	tests := []struct {
+		typ  string
+		want int
+	}{
+		{"character varying(20)", 20},
+		{"character(5)", 5},
+		{"character varying", 0},
+		{"text", 0},
+		{"character varying(20)[]", 0},
+		{"numeric(10,2)", 0},
+	}
+	for _, tt := range tests {
+		if got := typeLength(tt.typ); got != tt.want {
+			t.Errorf("typeLength(%q) = %d, want %d", tt.typ, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		v     sql.NullString
+		limit int
+		want  sql.NullString
+	}{
+		{valid("abcdef"), 3, valid("abc")},
+		{valid("abc"), 3, valid("abc")},
+		{valid("ação"), 2, valid("aç")},
+		{sql.NullString{}, 3, sql.NullString{}},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.v, tt.limit); got != tt.want {
+			t.Errorf("truncate(%+v, %d) = %+v, want %+v", tt.v, tt.limit, got, tt.want)
+		}
+	}
+}
+
+// recordWriter keeps the rows written to it
+type recordWriter struct {
+	rows [][]sql.NullString
+}
+
+func (r *recordWriter) WriteRow(values []sql.NullString) error {
+	r.rows = append(r.rows, append([]sql.NullString(nil), values...))
+	return nil
+}
+
+func (r *recordWriter) Close() error { return nil }
+
+func TestMaskingWriter(t *testing.T) {
+	report := &MaskedColumn{Column: "name", Action: config.MaskFixed}
+	out := &recordWriter{}
+	w := &maskingWriter{w: out, masks: []columnMask{{index: 1, apply: maskFunc(config.MaskRule{Action: config.MaskFixed, Value: "x"}, ""), report: report}}}
+
+	for _, row := range [][]sql.NullString{{valid("1"), valid("ana")}, {valid("2"), {}}} {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatalf("WriteRow: %v", err)
+		}
+	}
+	if out.rows[0][1] != valid("x") || out.rows[0][0] != valid("1") {
+		t.Errorf("first row = %+v", out.rows[0])
+	}
+	if out.rows[1][1].Valid {
+		t.Errorf("NULL value was masked: %+v", out.rows[1][1])
+	}
+	if report.Values != 1 {
+		t.Errorf("report counts %d values, want 1", report.Values)
+	}
+}
+
+func TestJSONMaskingWriter(t *testing.T) {
+	emailReport := &MaskedColumn{Column: "email"}
+	docReport := &MaskedColumn{Column: "doc"}
+	idReport := &MaskedColumn{Column: "code"}
+	out := &recordWriter{}
+	w := newJSONMaskingWriter(out, []columnMask{
+		{name: "email", apply: maskFunc(config.MaskRule{Action: config.MaskFixed, Value: "<a&b>"}, ""), report: emailReport},
+		{name: "doc", apply: maskFunc(config.MaskRule{Action: config.MaskNull}, ""), report: docReport},
+		{name: "code", apply: maskFunc(config.MaskRule{Action: config.MaskRedact, Keep: 1}, ""), report: idReport},
+	})
+
+	rows := []string{
+		`{"id":1,"email":"ana@exemplo.com","doc":"123","code":42,"tags":["a","b"]}`,
+		`{"id":2,"email":null,"doc":"456","code":null,"tags":null}`,
+	}
+	want := []string{
+		`{"id":1,"email":"<a&b>","doc":null,"code":"*2","tags":["a","b"]}`,
+		`{"id":2,"email":null,"doc":null,"code":null,"tags":null}`,
+	}
+	for _, row := range rows {
+		if err := w.WriteRow([]sql.NullString{valid(row)}); err != nil {
+			t.Fatalf("WriteRow(%s): %v", row, err)
+		}
+	}
+	for i := range want {
+		if got := out.rows[i][0].String; got != want[i] {
+			t.Errorf("row %d = %s, want %s", i, got, want[i])
+		}
+	}
+	if emailReport.Values != 1 || docReport.Values != 2 || idReport.Values != 1 {
+		t.Errorf("reports count email %d, doc %d, code %d values; want 1, 2, 1", emailReport.Values, docReport.Values, idReport.Values)
+	}
+
+	if err := w.WriteRow([]sql.NullString{valid(`not json`)}); err == nil {
+		t.Error("WriteRow accepted an invalid JSON object")
+	}
+}
@@ -0,0 +1,122 @@
+package export
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// parquetRowGroupRows is the number of rows buffered per row group, bounding memory use on large tables
+const parquetRowGroupRows = 50000
+
+// parquetTypes maps PostgreSQL types to Parquet types; anything else is written as UTF8 text
+var parquetTypes = map[string]parquet.Type{
+	"boolean":          parquet.BooleanType,
+	"smallint":         parquet.Int32Type,
+	"integer":          parquet.Int32Type,
+	"bigint":           parquet.Int64Type,
+	"real":             parquet.FloatType,
+	"double precision": parquet.DoubleType,
+}
+
+// parquetGroup is the root of the file schema. parquet.Group sorts its fields by name, so the
+// fields are kept in a slice to preserve the table's column order.
+type parquetGroup struct {
+	parquet.Group
+	fields []parquet.Field
+}
+
+func (g parquetGroup) Fields() []parquet.Field { return g.fields }
+
+// parquetField is a named column of parquetGroup
+type parquetField struct {
+	parquet.Node
+	name string
+}
+
+func (f parquetField) Name() string { return f.name }
+
+// Value is only used when writing Go structs; rows are written as parquet.Row values
+func (f parquetField) Value(reflect.Value) reflect.Value { return reflect.Value{} }
+
+// parquetWriter streams rows into a Parquet file with one optional column per table column
+type parquetWriter struct {
+	w     *parquet.Writer
+	kinds []parquet.Kind
+	row   parquet.Row
+}
+
+// newParquetWriter prepares a writer for columns
+func newParquetWriter(w io.Writer, columns []Column) (*parquetWriter, error) {
+	pw := &parquetWriter{row: make(parquet.Row, len(columns))}
+	root := parquetGroup{Group: parquet.Group{}}
+	for _, c := range columns {
+		leaf := parquet.String()
+		if typ, ok := parquetTypes[c.Type]; ok {
+			leaf = parquet.Leaf(typ)
+		}
+		root.Group[c.Name] = parquet.Optional(leaf)
+		root.fields = append(root.fields, parquetField{Node: parquet.Optional(leaf), name: c.Name})
+		pw.kinds = append(pw.kinds, leaf.Type().Kind())
+	}
+
+	config, err := parquet.NewWriterConfig(
+		parquet.NewSchema("export", root),
+		parquet.MaxRowsPerRowGroup(parquetRowGroupRows),
+		parquet.CreatedBy("snapTUI", "", ""),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure parquet writer: %w", err)
+	}
+	pw.w = parquet.NewWriter(w, config)
+	return pw, nil
+}
+
+// WriteRow converts one row of text values to the column types and writes it
+func (pw *parquetWriter) WriteRow(values []sql.NullString) error {
+	for i, v := range values {
+		if !v.Valid {
+			pw.row[i] = parquet.NullValue().Level(0, 0, i)
+			continue
+		}
+		value, err := parquetValue(pw.kinds[i], v.String)
+		if err != nil {
+			return fmt.Errorf("column %s: %w", pw.w.Schema().Fields()[i].Name(), err)
+		}
+		pw.row[i] = value.Level(0, 1, i)
+	}
+	_, err := pw.w.WriteRows([]parquet.Row{pw.row})
+	return err
+}
+
+// Close flushes buffered rows and writes the footer
+func (pw *parquetWriter) Close() error {
+	return pw.w.Close()
+}
+
+// parquetValue parses a value in PostgreSQL's text output as a Parquet value of kind
+func parquetValue(kind parquet.Kind, s string) (parquet.Value, error) {
+	switch kind {
+	case parquet.Boolean:
+		b, err := strconv.ParseBool(s)
+		return parquet.BooleanValue(b), err
+	case parquet.Int32:
+		n, err := strconv.ParseInt(s, 10, 32)
+		return parquet.Int32Value(int32(n)), err
+	case parquet.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		return parquet.Int64Value(n), err
+	case parquet.Float:
+		f, err := strconv.ParseFloat(s, 32)
+		return parquet.FloatValue(float32(f)), err
+	case parquet.Double:
+		f, err := strconv.ParseFloat(s, 64)
+		return parquet.DoubleValue(f), err
+	default:
+		return parquet.ByteArrayValue([]byte(s)), nil
+	}
+}
@@ -0,0 +1,132 @@
+package export
+
+import (
+	"bytes"
+	"database/sql"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestParquetWriterRoundTrip(t *testing.T) {
+	columns := []Column{
+		{Name: "id", Type: "integer"},
+		{Name: "total", Type: "bigint"},
+		{Name: "price", Type: "double precision"},
+		{Name: "ratio", Type: "real"},
+		{Name: "active", Type: "boolean"},
+		{Name: "name", Type: "text"},
+	}
+	valid := func(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
+	null := sql.NullString{}
+	rows := [][]sql.NullString{
+		{valid("1"), valid("9000000000"), valid("10.5"), valid("0.25"), valid("t"), valid("ação")},
+		{null, null, null, null, null, null},
+		{valid("-3"), valid("-1"), valid("-0.125"), null, valid("f"), valid("")},
+		{valid("42"), null, valid("3"), valid("1.5"), valid("true"), null},
+	}
+
+	var buf bytes.Buffer
+	pw, err := newParquetWriter(&buf, columns)
+	if err != nil {
+		t.Fatalf("newParquetWriter: %v", err)
+	}
+	for _, row := range rows {
+		if err := pw.WriteRow(row); err != nil {
+			t.Fatalf("WriteRow: %v", err)
+		}
+	}
+	if err := pw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	if f.NumRows() != int64(len(rows)) {
+		t.Fatalf("NumRows = %d, want %d", f.NumRows(), len(rows))
+	}
+
+	wantKinds := []parquet.Kind{parquet.Int32, parquet.Int64, parquet.Double, parquet.Float, parquet.Boolean, parquet.ByteArray}
+	fields := f.Schema().Fields()
+	if len(fields) != len(columns) {
+		t.Fatalf("schema has %d fields, want %d", len(fields), len(columns))
+	}
+	for i, field := range fields {
+		if field.Name() != columns[i].Name {
+			t.Errorf("field %d name = %q, want %q", i, field.Name(), columns[i].Name)
+		}
+		if !field.Optional() {
+			t.Errorf("field %s is not optional", field.Name())
+		}
+		if kind := field.Type().Kind(); kind != wantKinds[i] {
+			t.Errorf("field %s kind = %v, want %v", field.Name(), kind, wantKinds[i])
+		}
+	}
+	if lt := fields[5].Type().LogicalType(); lt == nil || lt.UTF8 == nil {
+		t.Errorf("field name is not annotated as UTF8")
+	}
+
+	want := [][]any{
+		{int32(1), int64(9000000000), 10.5, float32(0.25), true, "ação"},
+		{nil, nil, nil, nil, nil, nil},
+		{int32(-3), int64(-1), -0.125, nil, false, ""},
+		{int32(42), nil, 3.0, float32(1.5), true, nil},
+	}
+
+	reader := parquet.NewReader(f)
+	defer reader.Close()
+	got := make([]parquet.Row, len(rows)+1)
+	n, err := reader.ReadRows(got)
+	if err != nil && err != io.EOF {
+		t.Fatalf("ReadRows: %v", err)
+	}
+	if n != len(rows) {
+		t.Fatalf("read %d rows, want %d", n, len(rows))
+	}
+	for r, row := range got[:n] {
+		if len(row) != len(columns) {
+			t.Fatalf("row %d has %d values, want %d", r, len(row), len(columns))
+		}
+		for c, v := range row {
+			if got := readValue(v); got != want[r][c] {
+				t.Errorf("row %d column %s = %#v, want %#v", r, columns[c].Name, got, want[r][c])
+			}
+		}
+	}
+}
+
+// readValue converts a read value to the Go value the test expects, nil for nulls
+func readValue(v parquet.Value) any {
+	if v.IsNull() {
+		return nil
+	}
+	switch v.Kind() {
+	case parquet.Boolean:
+		return v.Boolean()
+	case parquet.Int32:
+		return v.Int32()
+	case parquet.Int64:
+		return v.Int64()
+	case parquet.Float:
+		return v.Float()
+	case parquet.Double:
+		return v.Double()
+	default:
+		return v.String()
+	}
+}
+
+func TestParquetWriterInvalidValue(t *testing.T) {
+	pw, err := newParquetWriter(io.Discard, []Column{{Name: "id", Type: "integer"}, {Name: "amount", Type: "bigint"}})
+	if err != nil {
+		t.Fatalf("newParquetWriter: %v", err)
+	}
+	err = pw.WriteRow([]sql.NullString{{String: "1", Valid: true}, {String: "abc", Valid: true}})
+	if err == nil || !strings.Contains(err.Error(), "column amount") {
+		t.Errorf("WriteRow error = %v, want one naming column amount", err)
+	}
+}
@@ -0,0 +1,210 @@
+package export
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Table describes a table of an exported database
+type Table struct {
+	Schema      string       `json:"schema"`
+	Name        string       `json:"name"`
+	Columns     []Column     `json:"columns"`
+	Constraints []Constraint `json:"constraints,omitempty"`
+	Indexes     []string     `json:"indexes,omitempty"`
+
+	oid int64
+}
+
+// Column describes a table column
+type Column struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	NotNull bool   `json:"not_null"`
+	Default string `json:"default,omitempty"`
+}
+
+// Constraint describes a table constraint by its SQL definition
+type Constraint struct {
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+}
+
+// QualifiedName returns schema.name
+func (t Table) QualifiedName() string {
+	return t.Schema + "." + t.Name
+}
+
+// tablesQuery lists ordinary tables (including partitions) outside system schemas
+const tablesQuery = `
+SELECT c.oid::int8, n.nspname, c.relname
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind = 'r'
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg\_toast%'
+  AND n.nspname NOT LIKE 'pg\_temp%'
+ORDER BY n.nspname, c.relname`
+
+// columnsQuery lists the columns of a table in definition order
+const columnsQuery = `
+SELECT a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull, COALESCE(pg_get_expr(d.adbin, d.adrelid), '')
+FROM pg_attribute a
+LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE a.attrelid = $1 AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY a.attnum`
+
+// constraintsQuery lists the constraints of a table
+const constraintsQuery = `
+SELECT conname, pg_get_constraintdef(oid)
+FROM pg_constraint
+WHERE conrelid = $1
+ORDER BY conname`
+
+// indexesQuery lists the index definitions of a table
+const indexesQuery = `
+SELECT pg_get_indexdef(indexrelid)
+FROM pg_index
+WHERE indrelid = $1
+ORDER BY 1`
+
+// queryer is satisfied by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// describeTables reads the tables of the database with their columns, constraints and indexes
+func describeTables(ctx context.Context, q queryer) ([]Table, error) {
+	rows, err := q.QueryContext(ctx, tablesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	var tables []Table
+	for rows.Next() {
+		var t Table
+		if err := rows.Scan(&t.oid, &t.Schema, &t.Name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan table: %w", err)
+		}
+		tables = append(tables, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over tables: %w", err)
+	}
+
+	for i := range tables {
+		if err := describeTable(ctx, q, &tables[i]); err != nil {
+			return nil, fmt.Errorf("failed to describe %s: %w", tables[i].QualifiedName(), err)
+		}
+	}
+	return tables, nil
+}
+
+// describeTable fills the columns, constraints and indexes of t
+func describeTable(ctx context.Context, q queryer, t *Table) error {
+	rows, err := q.QueryContext(ctx, columnsQuery, t.oid)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var c Column
+		if err := rows.Scan(&c.Name, &c.Type, &c.NotNull, &c.Default); err != nil {
+			rows.Close()
+			return err
+		}
+		t.Columns = append(t.Columns, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = q.QueryContext(ctx, constraintsQuery, t.oid)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var c Constraint
+		if err := rows.Scan(&c.Name, &c.Definition); err != nil {
+			rows.Close()
+			return err
+		}
+		t.Constraints = append(t.Constraints, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = q.QueryContext(ctx, indexesQuery, t.oid)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var def string
+		if err := rows.Scan(&def); err != nil {
+			return err
+		}
+		t.Indexes = append(t.Indexes, def)
+	}
+	return rows.Err()
+}
+
+// Schema reads the tables of dbname from the catalog with an empty search_path, so every
+// name and expression is schema-qualified the same way pg_dump writes them
+func (s *Service) Schema(ctx context.Context, dbname string) ([]Table, error) {
+	db, err := s.db.DB(ctx, dbname)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start schema transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "SELECT set_config('search_path', '', true)"); err != nil {
+		return nil, fmt.Errorf("failed to reset search_path: %w", err)
+	}
+	return describeTables(ctx, tx)
+}
+
+// ReadSchema reads schema.json from an export archive written by ExportDatabase
+func ReadSchema(path string) ([]Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s has no schema.json", filepath.Base(path))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+		}
+		if header.Name != "schema.json" {
+			continue
+		}
+		var tables []Table
+		if err := json.NewDecoder(tr).Decode(&tables); err != nil {
+			return nil, fmt.Errorf("failed to parse schema.json: %w", err)
+		}
+		return tables, nil
+	}
+}
@@ -0,0 +1,261 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lib/pq"
+)
+
+// Per-table export formats
+const (
+	FormatCSV     = "csv"
+	FormatJSONL   = "jsonl"
+	FormatParquet = "parquet"
+)
+
+// Formats lists the per-table export formats in display order
+var Formats = []string{FormatCSV, FormatJSONL, FormatParquet}
+
+// TableOptions selects the format and rows of a per-table export
+type TableOptions struct {
+	Format string
+	Where  string // SQL condition without the WHERE keyword, empty for all rows
+	Limit  int    // 0 for no limit
+}
+
+// Validate checks the options before any query is sent
+func (o TableOptions) Validate() error {
+	switch o.Format {
+	case FormatCSV, FormatJSONL, FormatParquet:
+	default:
+		return fmt.Errorf("unsupported export format %q", o.Format)
+	}
+	// The filter is spliced into the query, so a second statement is refused
+	if strings.Contains(o.Where, ";") {
+		return fmt.Errorf("the WHERE filter must not contain \";\"")
+	}
+	if o.Limit < 0 {
+		return fmt.Errorf("the row limit must not be negative")
+	}
+	return nil
+}
+
+// rowWriter writes exported rows in one output format
+type rowWriter interface {
+	WriteRow(values []sql.NullString) error
+	Close() error
+}
+
+// csvWriter writes rows in PostgreSQL's COPY CSV format with a header line
+type csvWriter struct {
+	w *bufio.Writer
+}
+
+func newCSVWriter(w *bufio.Writer, columns []Column) (*csvWriter, error) {
+	if err := writeCSVRecord(w, csvHeader(columns)); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: w}, nil
+}
+
+func (c *csvWriter) WriteRow(values []sql.NullString) error {
+	return writeCSVRecord(c.w, values)
+}
+
+func (c *csvWriter) Close() error {
+	return nil
+}
+
+// jsonlWriter writes one JSON object per line, as produced by row_to_json
+type jsonlWriter struct {
+	w *bufio.Writer
+}
+
+func (j *jsonlWriter) WriteRow(values []sql.NullString) error {
+	j.w.WriteString(values[0].String)
+	return j.w.WriteByte('\n')
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil
+}
+
+// ExportTable writes the rows of table matching opts into dir and returns the file path and row count.
+// Rows are streamed from the server and written as they arrive, so memory use does not grow with the table.
+// A non-nil masking profile is applied to the rows as in ExportDatabase.
+func (s *Service) ExportTable(ctx context.Context, dbname string, table types.TableInfo, opts TableOptions, dir string,
+	masking *config.MaskingProfile) (string, int64, error) {
+	if err := opts.Validate(); err != nil {
+		return "", 0, err
+	}
+	logger := s.logger.With("database", dbname, "table", table.QualifiedName(), "format", opts.Format)
+	start := time.Now()
+
+	db, err := s.db.DB(ctx, dbname)
+	if err != nil {
+		return "", 0, err
+	}
+	// Read-only, so a filter cannot modify data
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to start export transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	t := Table{Schema: table.Schema, Name: table.Name}
+	qualified := pq.QuoteIdentifier(t.Schema) + "." + pq.QuoteIdentifier(t.Name)
+	if err := tx.QueryRowContext(ctx, "SELECT $1::regclass::oid::int8", qualified).Scan(&t.oid); err != nil {
+		return "", 0, fmt.Errorf("failed to find %s: %w", table.QualifiedName(), err)
+	}
+	if err := describeTable(ctx, tx, &t); err != nil {
+		return "", 0, fmt.Errorf("failed to describe %s: %w", table.QualifiedName(), err)
+	}
+
+	// Masks are planned before the file is created, so an unsuitable rule leaves nothing behind
+	var masks []columnMask
+	suffix := opts.Format
+	if masking != nil {
+		if masks, _, err = planMasks(*masking, t); err != nil {
+			return "", 0, fmt.Errorf("masking profile %q: %w", masking.Name, err)
+		}
+		suffix = "masked." + opts.Format
+	}
+
+	filename := fmt.Sprintf("%s_%s_%s.%s", dbname, strings.ReplaceAll(table.QualifiedName(), "/", "_"),
+		start.Format("20060102_150405"), suffix)
+	path := filepath.Join(dir, filename)
+	f, err := os.Create(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer f.Close()
+	buf := bufio.NewWriter(f)
+
+	var w rowWriter
+	switch opts.Format {
+	case FormatCSV:
+		w, err = newCSVWriter(buf, t.Columns)
+	case FormatJSONL:
+		w = &jsonlWriter{w: buf}
+	case FormatParquet:
+		w, err = newParquetWriter(buf, t.Columns)
+	}
+	if err == nil && len(masks) > 0 {
+		if opts.Format == FormatJSONL {
+			w = newJSONMaskingWriter(w, masks)
+		} else {
+			w = &maskingWriter{w: w, masks: masks}
+		}
+		logger.Info("masking table export", "profile", masking.Name, "columns", len(masks))
+	}
+	if err == nil {
+		// JSON Lines rows arrive as a single row_to_json value
+		columns := len(t.Columns)
+		if opts.Format == FormatJSONL {
+			columns = 1
+		}
+		var rows int64
+		rows, err = copyRows(ctx, tx, selectQuery(t, opts, false), w, columns)
+		if err == nil {
+			err = w.Close()
+		}
+		if err == nil {
+			err = buf.Flush()
+		}
+		if err == nil {
+			err = f.Close()
+		}
+		if err == nil {
+			logger.Info("table export finished", "rows", rows, "duration", time.Since(start), "file", path)
+			return path, rows, nil
+		}
+	}
+
+	f.Close()
+	os.Remove(path)
+	logger.Error("table export failed", "error", err)
+	return "", 0, fmt.Errorf("failed to export %s: %w", table.QualifiedName(), err)
+}
+
+// selectQuery builds the query reading t: columns cast to text, or one row_to_json value per row
+// for JSON Lines. only restricts the scan to t itself, excluding inheritance children.
+func selectQuery(t Table, opts TableOptions, only bool) string {
+	from := pq.QuoteIdentifier(t.Schema) + "." + pq.QuoteIdentifier(t.Name)
+	if only {
+		from = "ONLY " + from
+	}
+	// The filter is closed on its own line so a trailing -- comment cannot swallow the rest of the query
+	if opts.Where != "" {
+		from += " WHERE (" + opts.Where + "\n)"
+	}
+	if opts.Limit > 0 {
+		from += fmt.Sprintf(" LIMIT %d", opts.Limit)
+	}
+
+	if opts.Format == FormatJSONL {
+		return "SELECT row_to_json(r)::text FROM (SELECT * FROM " + from + ") r"
+	}
+	columns := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		columns[i] = pq.QuoteIdentifier(c.Name) + "::text"
+	}
+	return "SELECT " + strings.Join(columns, ", ") + " FROM " + from
+}
+
+// copyRows streams the rows of query, each with the given number of text columns, into w
+// and returns how many were written. lib/pq cannot read COPY TO STDOUT, so this stands in for
+// it: there is no binary format, the CSV escaping is ours rather than COPY's, and wide tables
+// are slower since every column is a separate text value.
+func copyRows(ctx context.Context, tx *sql.Tx, query string, w rowWriter, columns int) (int64, error) {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	values := make([]sql.NullString, columns)
+	dest := make([]any, len(values))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var count int64
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return count, err
+		}
+		if err := w.WriteRow(values); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+// ExportTablesCmd creates a command that exports each table of dbname into dir, applying
+// masking when it is not nil
+func (s *Service) ExportTablesCmd(dbname string, tables []types.TableInfo, opts TableOptions, dir string, masking *config.MaskingProfile) tea.Cmd {
+	return func() tea.Msg {
+		var msg types.TableExportCompleteMsg
+		for _, t := range tables {
+			path, rows, err := s.ExportTable(context.Background(), dbname, t, opts, dir, masking)
+			if err != nil {
+				msg.Errors = append(msg.Errors, err.Error())
+				continue
+			}
+			msg.Files = append(msg.Files, filepath.Base(path))
+			msg.Rows += rows
+		}
+		return msg
+	}
+}
@@ -0,0 +1,83 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectQuery(t *testing.T) {
+	table := Table{Schema: "public", Name: "Order Items", Columns: []Column{{Name: "id"}, {Name: "note"}}}
+	tests := []struct {
+		name string
+		opts TableOptions
+		only bool
+		want string
+	}{
+		{
+			name: "all rows",
+			opts: TableOptions{Format: FormatCSV},
+			want: `SELECT "id"::text, "note"::text FROM "public"."Order Items"`,
+		},
+		{
+			name: "only the table itself",
+			opts: TableOptions{Format: FormatCSV},
+			only: true,
+			want: `SELECT "id"::text, "note"::text FROM ONLY "public"."Order Items"`,
+		},
+		{
+			name: "filter and limit",
+			opts: TableOptions{Format: FormatParquet, Where: "id > 10", Limit: 5},
+			want: "SELECT \"id\"::text, \"note\"::text FROM \"public\".\"Order Items\" WHERE (id > 10\n) LIMIT 5",
+		},
+		{
+			name: "json lines",
+			opts: TableOptions{Format: FormatJSONL, Where: "id > 10", Limit: 5},
+			want: "SELECT row_to_json(r)::text FROM (SELECT * FROM \"public\".\"Order Items\" WHERE (id > 10\n) LIMIT 5) r",
+		},
+		{
+			name: "trailing line comment stays inside the filter",
+			opts: TableOptions{Format: FormatJSONL, Where: "id > 10 -- recent", Limit: 5},
+			want: "SELECT row_to_json(r)::text FROM (SELECT * FROM \"public\".\"Order Items\" WHERE (id > 10 -- recent\n) LIMIT 5) r",
+		},
+		{
+			name: "or is grouped",
+			opts: TableOptions{Format: FormatCSV, Where: "id = 1 OR id = 2", Limit: 1},
+			want: "SELECT \"id\"::text, \"note\"::text FROM \"public\".\"Order Items\" WHERE (id = 1 OR id = 2\n) LIMIT 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectQuery(table, tt.opts, tt.only); got != tt.want {
+				t.Errorf("selectQuery =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		opts TableOptions
+		err  string
+	}{
+		{"csv", TableOptions{Format: FormatCSV}, ""},
+		{"filter with comment", TableOptions{Format: FormatJSONL, Where: "id > 1 -- x", Limit: 10}, ""},
+		{"unknown format", TableOptions{Format: "xml"}, `unsupported export format "xml"`},
+		{"second statement", TableOptions{Format: FormatCSV, Where: "true; DROP TABLE t"}, "must not contain"},
+		{"negative limit", TableOptions{Format: FormatCSV, Limit: -1}, "must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Validate error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
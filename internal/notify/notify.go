@@ -0,0 +1,200 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Summary represents the result of a backup run sent to notification targets
+type Summary struct {
+	Event      string    `json:"event"`
+	Profile    string    `json:"profile"`
+	Host       string    `json:"host"`
+	Success    int       `json:"success"`
+	Failures   int       `json:"failures"`
+	Errors     []string  `json:"errors"`
+	Filenames  []string  `json:"filenames"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// Notification events
+const (
+	EventSuccess = "backup.success"
+	EventFailure = "backup.failure"
+)
+
+// NewSummary builds a summary from a completed backup run
+func NewSummary(profile, host string, msg types.BackupCompleteMsg) Summary {
+	event := EventSuccess
+	if len(msg.Errors) > 0 {
+		event = EventFailure
+	}
+
+	return Summary{
+		Event:      event,
+		Profile:    profile,
+		Host:       host,
+		Success:    msg.Success,
+		Failures:   len(msg.Errors),
+		Errors:     msg.Errors,
+		Filenames:  msg.Filenames,
+		StartedAt:  msg.StartedAt,
+		FinishedAt: msg.FinishedAt,
+	}
+}
+
+// Failed reports whether the run had any error
+func (s Summary) Failed() bool {
+	return s.Event == EventFailure
+}
+
+// Text renders the summary as a short human readable message
+func (s Summary) Text() string {
+	status := "concluído com sucesso"
+	if s.Failed() {
+		status = "concluído com erros"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "snapTUI: backup %s (%s @ %s)\n", status, s.Profile, s.Host)
+	fmt.Fprintf(&b, "Sucesso: %d  Erros: %d  Duração: %s\n", s.Success, s.Failures, s.FinishedAt.Sub(s.StartedAt).Round(time.Second))
+	for _, filename := range s.Filenames {
+		fmt.Fprintf(&b, "  • %s\n", filename)
+	}
+	for _, err := range s.Errors {
+		fmt.Fprintf(&b, "  ✗ %s\n", err)
+	}
+	return b.String()
+}
+
+// Service dispatches backup summaries to the configured targets
+type Service struct {
+	targets []config.NotificationTarget
+	client  *http.Client
+	logger  *slog.Logger
+}
+
+// NewService creates a new notification service
+func NewService(settings config.NotificationSettings, logger *slog.Logger) *Service {
+	return &Service{
+		targets: settings.Targets,
+		client:  &http.Client{Timeout: 15 * time.Second},
+		logger:  logger,
+	}
+}
+
+// Send delivers the summary to every target whose policy matches the run outcome
+func (s *Service) Send(ctx context.Context, summary Summary) []error {
+	var errs []error
+	for _, target := range s.targets {
+		if summary.Failed() && !target.OnFailure || !summary.Failed() && !target.OnSuccess {
+			continue
+		}
+
+		var err error
+		switch target.Type {
+		case config.NotifyWebhook:
+			err = s.postJSON(ctx, target.URL, summary)
+		case config.NotifySlack:
+			err = s.postJSON(ctx, target.URL, map[string]string{"text": summary.Text()})
+		case config.NotifyEmail:
+			err = sendEmail(target, summary)
+		default:
+			err = fmt.Errorf("unknown target type %q", target.Type)
+		}
+		if err != nil {
+			s.logger.Error("notification failed", "type", target.Type, "event", summary.Event, "error", err)
+			errs = append(errs, fmt.Errorf("%s notification failed: %w", target.Type, err))
+			continue
+		}
+		s.logger.Info("notification sent", "type", target.Type, "event", summary.Event)
+	}
+	return errs
+}
+
+// SendCmd creates a command that delivers the summary in the background
+func (s *Service) SendCmd(summary Summary) tea.Cmd {
+	if len(s.targets) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		var messages []string
+		for _, err := range s.Send(context.Background(), summary) {
+			messages = append(messages, err.Error())
+		}
+		return types.NotificationSentMsg{Errors: messages}
+	}
+}
+
+// postJSON sends payload as a JSON POST request
+func (s *Service) postJSON(ctx context.Context, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status from %s: %s", url, resp.Status)
+	}
+	return nil
+}
+
+// sendEmail delivers the summary through SMTP
+func sendEmail(target config.NotificationTarget, summary Summary) error {
+	if len(target.To) == 0 {
+		return fmt.Errorf("no recipients configured")
+	}
+
+	port := target.SMTPPort
+	if port == "" {
+		port = "25"
+	}
+	addr := net.JoinHostPort(target.SMTPHost, port)
+
+	var auth smtp.Auth
+	if target.SMTPUser != "" {
+		auth = smtp.PlainAuth("", target.SMTPUser, target.SMTPPassword, target.SMTPHost)
+	}
+
+	subject := "[snapTUI] Backup concluído"
+	if summary.Failed() {
+		subject = "[snapTUI] Backup com erros"
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", target.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(target.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(summary.Text(), "\n", "\r\n"))
+
+	if err := smtp.SendMail(addr, auth, target.From, target.To, []byte(msg.String())); err != nil {
+		return fmt.Errorf("failed to send email via %s: %w", addr, err)
+	}
+	return nil
+}
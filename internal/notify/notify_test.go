@@ -0,0 +1,336 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// recorder is a webhook endpoint that keeps the request bodies it receives
+type recorder struct {
+	mu     sync.Mutex
+	bodies [][]byte
+	status int
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if req.Method == http.MethodPost && req.Header.Get("Content-Type") == "application/json" {
+		r.bodies = append(r.bodies, body)
+	}
+	if r.status != 0 {
+		w.WriteHeader(r.status)
+	}
+}
+
+func (r *recorder) received() [][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.bodies
+}
+
+func newRecorder(t *testing.T) (*recorder, string) {
+	rec := &recorder{}
+	server := httptest.NewServer(rec)
+	t.Cleanup(server.Close)
+	return rec, server.URL
+}
+
+func testSummary(errors []string) Summary {
+	started := time.Date(2024, 5, 10, 2, 0, 0, 0, time.UTC)
+	return NewSummary("producao", "db.exemplo.com", types.BackupCompleteMsg{
+		Success:    2,
+		Errors:     errors,
+		Filenames:  []string{"shop_20240510_020000.backup", "crm_20240510_020000.backup"},
+		StartedAt:  started,
+		FinishedAt: started.Add(90 * time.Second),
+	})
+}
+
+func TestSendWebhookPayload(t *testing.T) {
+	rec, url := newRecorder(t)
+	service := NewService(config.NotificationSettings{Targets: []config.NotificationTarget{
+		{Type: config.NotifyWebhook, URL: url, OnSuccess: true},
+	}}, slog.New(slog.DiscardHandler))
+
+	summary := testSummary(nil)
+	if errs := service.Send(context.Background(), summary); len(errs) > 0 {
+		t.Fatalf("Send: %v", errs)
+	}
+
+	bodies := rec.received()
+	if len(bodies) != 1 {
+		t.Fatalf("webhook received %d requests, want 1", len(bodies))
+	}
+	var got Summary
+	if err := json.Unmarshal(bodies[0], &got); err != nil {
+		t.Fatalf("payload is not a summary: %v", err)
+	}
+	if !reflect.DeepEqual(got, summary) {
+		t.Errorf("payload = %+v, want %+v", got, summary)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(bodies[0], &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"event", "profile", "host", "success", "failures", "errors", "filenames", "started_at", "finished_at"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("payload has no %q field", key)
+		}
+	}
+	if fields["event"] != EventSuccess {
+		t.Errorf("event = %v, want %s", fields["event"], EventSuccess)
+	}
+}
+
+func TestSendSlackPayload(t *testing.T) {
+	rec, url := newRecorder(t)
+	service := NewService(config.NotificationSettings{Targets: []config.NotificationTarget{
+		{Type: config.NotifySlack, URL: url, OnFailure: true},
+	}}, slog.New(slog.DiscardHandler))
+
+	summary := testSummary([]string{"crm: connection refused"})
+	if errs := service.Send(context.Background(), summary); len(errs) > 0 {
+		t.Fatalf("Send: %v", errs)
+	}
+
+	bodies := rec.received()
+	if len(bodies) != 1 {
+		t.Fatalf("slack received %d requests, want 1", len(bodies))
+	}
+	var got map[string]string
+	if err := json.Unmarshal(bodies[0], &got); err != nil {
+		t.Fatalf("payload is not a slack message: %v", err)
+	}
+	if want := map[string]string{"text": summary.Text()}; !reflect.DeepEqual(got, want) {
+		t.Errorf("payload = %q, want %q", got, want)
+	}
+}
+
+func TestSendPolicies(t *testing.T) {
+	tests := []struct {
+		name      string
+		onSuccess bool
+		onFailure bool
+		failed    bool
+		want      int
+	}{
+		{"success only, run succeeded", true, false, false, 1},
+		{"success only, run failed", true, false, true, 0},
+		{"failure only, run succeeded", false, true, false, 0},
+		{"failure only, run failed", false, true, true, 1},
+		{"both, run succeeded", true, true, false, 1},
+		{"both, run failed", true, true, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, url := newRecorder(t)
+			service := NewService(config.NotificationSettings{Targets: []config.NotificationTarget{
+				{Type: config.NotifyWebhook, URL: url, OnSuccess: tt.onSuccess, OnFailure: tt.onFailure},
+			}}, slog.New(slog.DiscardHandler))
+
+			var errors []string
+			if tt.failed {
+				errors = []string{"shop: disk full"}
+			}
+			if errs := service.Send(context.Background(), testSummary(errors)); len(errs) > 0 {
+				t.Fatalf("Send: %v", errs)
+			}
+			if got := len(rec.received()); got != tt.want {
+				t.Errorf("webhook received %d requests, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendReportsHTTPErrors(t *testing.T) {
+	failing, failingURL := newRecorder(t)
+	failing.status = http.StatusInternalServerError
+	ok, okURL := newRecorder(t)
+	service := NewService(config.NotificationSettings{Targets: []config.NotificationTarget{
+		{Type: config.NotifyWebhook, URL: failingURL, OnSuccess: true},
+		{Type: config.NotifyWebhook, URL: okURL, OnSuccess: true},
+	}}, slog.New(slog.DiscardHandler))
+
+	errs := service.Send(context.Background(), testSummary(nil))
+	if len(errs) != 1 {
+		t.Fatalf("Send returned %d errors, want 1: %v", len(errs), errs)
+	}
+	// A failing target does not stop delivery to the others
+	if len(failing.received()) != 1 || len(ok.received()) != 1 {
+		t.Errorf("requests: failing %d, ok %d; want 1 each", len(failing.received()), len(ok.received()))
+	}
+}
+
+// smtpStub is an SMTP server that accepts one session and keeps the envelope and message
+type smtpStub struct {
+	mu         sync.Mutex
+	from       string
+	to         []string
+	data       string
+	rejectRcpt bool
+	done       chan struct{}
+}
+
+func newSMTPStub(t *testing.T, rejectRcpt bool) (*smtpStub, string, string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	stub := &smtpStub{rejectRcpt: rejectRcpt, done: make(chan struct{})}
+	go func() {
+		defer close(stub.done)
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		stub.serve(textproto.NewConn(conn))
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	return stub, host, port
+}
+
+// serve speaks the minimal SMTP dialog smtp.SendMail needs
+func (s *smtpStub) serve(tp *textproto.Conn) {
+	tp.PrintfLine("220 stub ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			tp.PrintfLine("250 stub")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			s.mu.Lock()
+			s.from = line[len("MAIL FROM:"):]
+			s.mu.Unlock()
+			tp.PrintfLine("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			if s.rejectRcpt {
+				tp.PrintfLine("550 mailbox unavailable")
+				continue
+			}
+			s.mu.Lock()
+			s.to = append(s.to, line[len("RCPT TO:"):])
+			s.mu.Unlock()
+			tp.PrintfLine("250 OK")
+		case command == "DATA":
+			tp.PrintfLine("354 end with .")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.data = string(data)
+			s.mu.Unlock()
+			tp.PrintfLine("250 queued")
+		case command == "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("250 OK")
+		}
+	}
+}
+
+// session waits for the session to end and returns what the stub received
+func (s *smtpStub) session(t *testing.T) (from string, to []string, data string) {
+	select {
+	case <-s.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SMTP session did not finish")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.from, s.to, s.data
+}
+
+func TestSendEmail(t *testing.T) {
+	stub, host, port := newSMTPStub(t, false)
+	service := NewService(config.NotificationSettings{Targets: []config.NotificationTarget{{
+		Type:      config.NotifyEmail,
+		OnFailure: true,
+		SMTPHost:  host,
+		SMTPPort:  port,
+		From:      "backup@exemplo.com",
+		To:        []string{"dba@exemplo.com", "ops@exemplo.com"},
+	}}}, slog.New(slog.DiscardHandler))
+
+	summary := testSummary([]string{"crm: connection refused"})
+	if errs := service.Send(context.Background(), summary); len(errs) > 0 {
+		t.Fatalf("Send: %v", errs)
+	}
+
+	from, to, data := stub.session(t)
+	if from != "<backup@exemplo.com>" {
+		t.Errorf("MAIL FROM = %q, want <backup@exemplo.com>", from)
+	}
+	if want := []string{"<dba@exemplo.com>", "<ops@exemplo.com>"}; !reflect.DeepEqual(to, want) {
+		t.Errorf("RCPT TO = %q, want %q", to, want)
+	}
+
+	// ReadDotBytes turns the CRLF line endings into LF
+	header, body, ok := strings.Cut(data, "\n\n")
+	if !ok {
+		t.Fatalf("message has no header separator: %q", data)
+	}
+	for _, want := range []string{
+		"From: backup@exemplo.com",
+		"To: dba@exemplo.com, ops@exemplo.com",
+		"Subject: [snapTUI] Backup com erros",
+		"Content-Type: text/plain; charset=UTF-8",
+	} {
+		if !strings.Contains(header+"\n", want+"\n") {
+			t.Errorf("headers have no %q:\n%s", want, header)
+		}
+	}
+	if body != summary.Text() {
+		t.Errorf("body = %q, want %q", body, summary.Text())
+	}
+	for _, want := range []string{"producao @ db.exemplo.com", "Sucesso: 2  Erros: 1", "shop_20240510_020000.backup", "crm: connection refused"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body has no %q", want)
+		}
+	}
+}
+
+func TestSendEmailReportsSMTPErrors(t *testing.T) {
+	stub, host, port := newSMTPStub(t, true)
+	service := NewService(config.NotificationSettings{Targets: []config.NotificationTarget{{
+		Type:      config.NotifyEmail,
+		OnSuccess: true,
+		SMTPHost:  host,
+		SMTPPort:  port,
+		From:      "backup@exemplo.com",
+		To:        []string{"dba@exemplo.com"},
+	}}}, slog.New(slog.DiscardHandler))
+
+	errs := service.Send(context.Background(), testSummary(nil))
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "550") {
+		t.Fatalf("Send errors = %v, want the 550 reply", errs)
+	}
+	if _, _, data := stub.session(t); data != "" {
+		t.Errorf("a message was delivered after the recipient was rejected: %q", data)
+	}
+}
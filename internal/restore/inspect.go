@@ -0,0 +1,41 @@
+package restore
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// CountObjects counts the entries of each object type, most frequent first
+func CountObjects(entries []types.TOCEntry) []types.ObjectCount {
+	counts := make(map[string]int)
+	for _, e := range entries {
+		counts[e.Desc]++
+	}
+
+	result := make([]types.ObjectCount, 0, len(counts))
+	for desc, count := range counts {
+		result = append(result, types.ObjectCount{Desc: desc, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Desc < result[j].Desc
+	})
+	return result
+}
+
+// FilterEntries returns the indexes of the entries whose type, schema, name or owner contains
+// query, ignoring case; an empty query matches every entry
+func FilterEntries(entries []types.TOCEntry, query string) []int {
+	query = strings.ToLower(strings.TrimSpace(query))
+	matches := make([]int, 0, len(entries))
+	for i, e := range entries {
+		if query == "" || strings.Contains(strings.ToLower(e.Desc+" "+e.Namespace+" "+e.Tag+" "+e.Owner), query) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
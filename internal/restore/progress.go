@@ -0,0 +1,45 @@
+package restore
+
+import (
+	"strings"
+)
+
+// verbosePrefix starts every message pg_restore prints
+const verbosePrefix = "pg_restore: "
+
+// restoredObject returns the object named by a pg_restore --verbose line printed once per
+// restored TOC entry, e.g. `creating TABLE "public.users"` → "TABLE public.users"
+func restoredObject(line string) (string, bool) {
+	message, ok := strings.CutPrefix(line, verbosePrefix)
+	if !ok {
+		return "", false
+	}
+
+	var object string
+	switch {
+	case strings.HasPrefix(message, "creating "):
+		object = strings.TrimPrefix(message, "creating ")
+	case strings.HasPrefix(message, "processing data for table "):
+		object = "TABLE DATA " + strings.TrimPrefix(message, "processing data for table ")
+	case strings.HasPrefix(message, "executing "):
+		object = strings.TrimPrefix(message, "executing ")
+	default:
+		return "", false
+	}
+	return strings.ReplaceAll(object, `"`, ""), true
+}
+
+// informational reports whether a pg_restore line is --verbose chatter rather than an
+// error, warning or the context printed with them
+func informational(line string) bool {
+	message, ok := strings.CutPrefix(line, verbosePrefix)
+	if !ok {
+		return false
+	}
+	for _, prefix := range []string{"error:", "warning:", "detail:", "hint:", "["} {
+		if strings.HasPrefix(message, prefix) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package restore
+
+import "testing"
+
+func TestRestoredObject(t *testing.T) {
+	// Lines printed by pg_restore --verbose while restoring a custom-format dump
+	tests := []struct {
+		line   string
+		object string
+		ok     bool
+	}{
+		{`pg_restore: creating SCHEMA "Sales"`, "SCHEMA Sales", true},
+		{`pg_restore: creating TABLE "public.customers"`, "TABLE public.customers", true},
+		{`pg_restore: creating SEQUENCE OWNED BY "public.customers_id_seq"`, "SEQUENCE OWNED BY public.customers_id_seq", true},
+		{`pg_restore: creating DEFAULT "public.customers id"`, "DEFAULT public.customers id", true},
+		{`pg_restore: creating CONSTRAINT "public.customers customers_pkey"`, "CONSTRAINT public.customers customers_pkey", true},
+		{`pg_restore: creating FK CONSTRAINT "public.orders orders_customer_fkey"`, "FK CONSTRAINT public.orders orders_customer_fkey", true},
+		{`pg_restore: creating COMMENT "SCHEMA "public""`, "COMMENT SCHEMA public", true},
+		{`pg_restore: processing data for table "public.customers"`, "TABLE DATA public.customers", true},
+		{`pg_restore: executing SEQUENCE SET customers_id_seq`, "SEQUENCE SET customers_id_seq", true},
+		{`pg_restore: connecting to database for restore`, "", false},
+		{`pg_restore: error: could not execute query: ERROR:  relation "customers" already exists`, "", false},
+		{`pg_restore: warning: errors ignored on restore: 1`, "", false},
+		{`Command was: CREATE TABLE public.customers (`, "", false},
+		{``, "", false},
+	}
+	for _, tt := range tests {
+		object, ok := restoredObject(tt.line)
+		if object != tt.object || ok != tt.ok {
+			t.Errorf("restoredObject(%q) = %q, %v; want %q, %v", tt.line, object, ok, tt.object, tt.ok)
+		}
+	}
+}
+
+func TestInformational(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{`pg_restore: connecting to database for restore`, true},
+		{`pg_restore: creating TABLE "public.customers"`, true},
+		{`pg_restore: processing data for table "public.customers"`, true},
+		{`pg_restore: error: could not execute query: ERROR:  relation "customers" already exists`, false},
+		{`pg_restore: warning: errors ignored on restore: 1`, false},
+		{`pg_restore: detail: Key (id)=(1) already exists.`, false},
+		{`pg_restore: hint: Use DROP ... CASCADE to drop the dependent objects too.`, false},
+		{`pg_restore: [archiver (db)] Error while PROCESSING TOC:`, false},
+		{`Command was: CREATE TABLE public.customers (`, false},
+	}
+	for _, tt := range tests {
+		if got := informational(tt.line); got != tt.want {
+			t.Errorf("informational(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package restore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/backup"
+	"github.com/Luiz-F3lipe/snapTUI/internal/catalog"
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/database"
+	"github.com/Luiz-F3lipe/snapTUI/internal/logging"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// archiveExtensions lists the file extensions of archives pg_restore can read
+var archiveExtensions = []string{".backup", ".tar", ".dump"}
+
+// Options controls how an archive is restored into the target database
+type Options struct {
+	Clean        bool // drop existing objects before recreating them (--clean --if-exists)
+	CreateTarget bool // create the target database first
+	SafetyBackup bool // dump the existing target database before restoring
+	Jobs         int  // parallel pg_restore jobs (--jobs), custom-format archives only
+}
+
+// ProgressFunc receives progress updates while a restore runs
+type ProgressFunc func(types.RestoreProgressMsg)
+
+// Service restores pg_dump archives with pg_restore
+type Service struct {
+	logger  *slog.Logger
+	backup  *backup.Service
+	db      *database.Service
+	catalog *catalog.Service
+}
+
+// NewService creates a new restore service that finds clients and backup files through backup
+// and creates target databases through db
+func NewService(logger *slog.Logger, backup *backup.Service, db *database.Service, catalog *catalog.Service) *Service {
+	return &Service{logger: logger, backup: backup, db: db, catalog: catalog}
+}
+
+// Archives lists the restorable backup files, newest first: successful pg_dump runs from the
+// catalog whose file still exists, plus archives found in the backup directory
+func (s *Service) Archives() []types.ArchiveFile {
+	seen := make(map[string]bool)
+	var archives []types.ArchiveFile
+	add := func(archive types.ArchiveFile) {
+		if seen[archive.Path] || !isArchive(archive.Path) {
+			return
+		}
+		info, err := os.Stat(archive.Path)
+		if err != nil || info.IsDir() {
+			return
+		}
+		seen[archive.Path] = true
+		archive.Size = info.Size()
+		if archive.CreatedAt.IsZero() {
+			archive.CreatedAt = info.ModTime()
+		}
+		archives = append(archives, archive)
+	}
+
+	entries, err := s.catalog.Entries()
+	if err != nil {
+		s.logger.Warn("failed to read catalog", "error", err)
+	}
+	for _, e := range entries {
+		if e.Success && e.IsDump() && e.Path != "" {
+			add(types.ArchiveFile{Path: e.Path, Database: e.Database, Schema: e.Schema, Profile: e.Profile, CreatedAt: e.FinishedAt})
+		}
+	}
+
+	// Multi-server runs write into one subdirectory per profile and host
+	if dir, err := s.backup.BackupDir(); err == nil {
+		for _, pattern := range []string{"*", filepath.Join("*", "*")} {
+			matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+			for _, path := range matches {
+				add(types.ArchiveFile{Path: path})
+			}
+		}
+	}
+
+	sort.SliceStable(archives, func(i, j int) bool {
+		return archives[i].CreatedAt.After(archives[j].CreatedAt)
+	})
+	return archives
+}
+
+// isArchive reports whether path has the extension of a pg_restore archive
+func isArchive(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range archiveExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadTOC runs pg_restore --list on file and parses its table of contents
+func (s *Service) ReadTOC(file string, settings config.PgDumpSettings, serverVersion int) (types.TOCHeader, []types.TOCEntry, error) {
+	pgRestore, err := s.backup.FindPgRestore(settings, serverVersion)
+	if err != nil {
+		return types.TOCHeader{}, nil, err
+	}
+
+	cmd := pgRestore.CommandMounting([]string{filepath.Dir(file)}, "--list", file)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		s.logger.Error("pg_restore --list failed", "file", file, "error", err, "stderr", stderr.String())
+		return types.TOCHeader{}, nil, fmt.Errorf("failed to read table of contents of %s: %w\nOutput: %s",
+			filepath.Base(file), err, strings.TrimSpace(stderr.String()))
+	}
+
+	header, entries, err := ParseTOC(&stdout)
+	if err != nil {
+		return header, nil, err
+	}
+	s.logger.Debug("read table of contents", "file", file, "entries", len(entries))
+	return header, entries, nil
+}
+
+// LoadTOCCmd creates a command that reads the table of contents of file in the background
+func (s *Service) LoadTOCCmd(file string, settings config.PgDumpSettings, serverVersion int) tea.Cmd {
+	return func() tea.Msg {
+		header, entries, err := s.ReadTOC(file, settings, serverVersion)
+		return types.RestoreTOCLoadedMsg{File: file, Header: header, Entries: entries, Err: err}
+	}
+}
+
+// Restore restores the selected TOC entries of file into the target database through a
+// generated --use-list file, so only those objects are recreated. Progress is parsed from
+// pg_restore --verbose and reported to progress. It returns the safety backup file, if one
+// was requested, and the errors and warnings printed by pg_restore.
+func (s *Service) Restore(conn types.DatabaseConnection, file, target string, entries []types.TOCEntry, selected []int,
+	settings config.PgDumpSettings, opts Options, progress ProgressFunc) (safetyBackup, output string, err error) {
+	pgRestore, err := s.backup.FindPgRestore(settings, conn.ServerVersion)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch {
+	case opts.CreateTarget:
+		if err := s.db.CreateDatabase(context.Background(), target); err != nil {
+			return "", "", err
+		}
+		// A half-restored database would ask for the overwrite confirmation on the next attempt
+		defer func() {
+			if err == nil {
+				return
+			}
+			s.logger.Error("restore failed, dropping the created database", "database", target, "error", err)
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if dropErr := s.db.DropDatabase(ctx, target); dropErr != nil {
+				s.logger.Error("failed to drop the created database", "database", target, "error", dropErr)
+				err = fmt.Errorf("%w\nthe partial database %s could not be dropped: %v", err, target, dropErr)
+			}
+		}()
+	case opts.SafetyBackup:
+		progress(types.RestoreProgressMsg{SafetyBackup: true, Total: len(selected)})
+		if safetyBackup, err = s.safetyBackup(conn, target, settings); err != nil {
+			return "", "", fmt.Errorf("safety backup of %s failed, restore not started: %w", target, err)
+		}
+	}
+
+	listDir, err := os.MkdirTemp("", "snaptui-restore-")
+	if err != nil {
+		return safetyBackup, "", fmt.Errorf("failed to create restore list: %w", err)
+	}
+	defer os.RemoveAll(listDir)
+
+	listPath := filepath.Join(listDir, "use-list")
+	if err := os.WriteFile(listPath, []byte(useList(entries, selected)), 0o600); err != nil {
+		return safetyBackup, "", fmt.Errorf("failed to write restore list: %w", err)
+	}
+
+	host, port := conn.Address()
+	args := []string{
+		"--host", pgRestore.DialHost(host),
+		"--port", port,
+		"--username", conn.User,
+		"--no-password",
+		"--dbname", target,
+		"--use-list", listPath,
+		"--verbose",
+	}
+	if opts.Clean {
+		args = append(args, "--clean", "--if-exists")
+	}
+	if opts.Jobs > 1 {
+		args = append(args, "--jobs", strconv.Itoa(opts.Jobs))
+	}
+	cmd := pgRestore.CommandMounting([]string{filepath.Dir(file), listDir}, append(args, file)...)
+	cmd.Env = database.ClientEnv(conn)
+
+	pipe, err := cmd.StderrPipe()
+	if err != nil {
+		return safetyBackup, "", fmt.Errorf("failed to read pg_restore output: %w", err)
+	}
+	cmd.Stdout = cmd.Stderr
+
+	logger := s.logger.With("host", conn.Host, "database", target)
+	logger.Info("starting pg_restore", "command", logging.RedactArgs(cmd.Path, cmd.Args[1:]), "file", file, "entries", len(selected))
+	start := time.Now()
+
+	if err := cmd.Start(); err != nil {
+		return safetyBackup, "", fmt.Errorf("failed to start pg_restore: %w", err)
+	}
+
+	// Every restored entry prints one line; parallel workers interleave theirs
+	var messages bytes.Buffer
+	done := 0
+	progress(types.RestoreProgressMsg{Total: len(selected)})
+	scanner := bufio.NewScanner(pipe)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if object, ok := restoredObject(line); ok {
+			done = min(done+1, len(selected))
+			progress(types.RestoreProgressMsg{Done: done, Total: len(selected), Current: object})
+			continue
+		}
+		if !informational(line) {
+			messages.WriteString(line + "\n")
+		}
+	}
+	// Drain whatever the scanner could not read so pg_restore never blocks on a full pipe
+	io.Copy(io.Discard, pipe)
+
+	output = strings.TrimSpace(messages.String())
+	if err := cmd.Wait(); err != nil {
+		logger.Error("pg_restore failed", "duration", time.Since(start), "error", err, "stderr", logging.Redact(output))
+		return safetyBackup, "", fmt.Errorf("failed to execute pg_restore into %s: %w\nOutput: %s", target, err, output)
+	}
+	if output != "" {
+		logger.Warn("pg_restore reported messages", "stderr", logging.Redact(output))
+	}
+
+	logger.Info("pg_restore finished", "duration", time.Since(start), "entries", done)
+	return safetyBackup, output, nil
+}
+
+// safetyBackup dumps the target database before it is restored over, recording the
+// run in the catalog like any other backup, and returns the file name
+func (s *Service) safetyBackup(conn types.DatabaseConnection, target string, settings config.PgDumpSettings) (string, error) {
+	entry := catalog.Entry{
+		Host:      conn.Host,
+		Port:      conn.Port,
+		Database:  target,
+		Kind:      catalog.KindDump,
+		StartedAt: time.Now(),
+	}
+
+	s.logger.Info("taking safety backup before restore", "host", conn.Host, "database", target)
+	filename, err := s.backup.BackupDatabase(conn, target, settings)
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Success = true
+		if dir, dirErr := s.backup.BackupDir(); dirErr == nil {
+			entry.Path = filepath.Join(dir, filename)
+		}
+	}
+
+	entry.FinishedAt = time.Now()
+	if info, statErr := os.Stat(entry.Path); entry.Path != "" && statErr == nil {
+		entry.OutputSize = info.Size()
+	}
+	if recordErr := s.catalog.Record(entry); recordErr != nil {
+		s.logger.Error("failed to record backup in catalog", "database", target, "error", recordErr)
+	}
+	return filename, err
+}
+
+// useList renders the --use-list file: the original TOC lines of the selected entries
+func useList(entries []types.TOCEntry, selected []int) string {
+	var b strings.Builder
+	for _, i := range selected {
+		b.WriteString(entries[i].Line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// StartRestore runs a selective restore in the background. The returned channel delivers
+// RestoreProgressMsg updates followed by a final RestoreCompleteMsg, then is closed.
+func (s *Service) StartRestore(conn types.DatabaseConnection, file, target string, entries []types.TOCEntry, selected []int,
+	settings config.PgDumpSettings, opts Options) <-chan tea.Msg {
+	updates := make(chan tea.Msg, 1)
+	go func() {
+		defer close(updates)
+		startedAt := time.Now()
+
+		// Keep only the latest progress so a slow UI never stalls pg_restore's output
+		progress := func(msg types.RestoreProgressMsg) {
+			select {
+			case <-updates:
+			default:
+			}
+			updates <- msg
+		}
+		safetyBackup, warnings, err := s.Restore(conn, file, target, entries, selected, settings, opts, progress)
+
+		select {
+		case <-updates:
+		default:
+		}
+		updates <- types.RestoreCompleteMsg{
+			Target:       target,
+			Entries:      len(selected),
+			SafetyBackup: safetyBackup,
+			Warnings:     warnings,
+			Err:          err,
+			StartedAt:    startedAt,
+			FinishedAt:   time.Now(),
+		}
+	}()
+	return updates
+}
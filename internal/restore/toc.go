@@ -0,0 +1,145 @@
+package restore
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// tocLinePattern matches "<dump id>; <catalog oid> <object oid> <rest>"
+var tocLinePattern = regexp.MustCompile(`^(\d+); (\d+) (\d+) (.*)$`)
+
+// tocDescs lists the object types pg_restore prints that contain spaces; longer ones are tried first
+var tocDescs = []string{
+	"ACCESS METHOD",
+	"BLOB METADATA",
+	"DATABASE PROPERTIES",
+	"DEFAULT ACL",
+	"EVENT TRIGGER",
+	"FK CONSTRAINT",
+	"FOREIGN DATA WRAPPER",
+	"FOREIGN SERVER",
+	"FOREIGN TABLE",
+	"INDEX ATTACH",
+	"LARGE OBJECT",
+	"MATERIALIZED VIEW",
+	"MATERIALIZED VIEW DATA",
+	"OPERATOR CLASS",
+	"OPERATOR FAMILY",
+	"PROCEDURAL LANGUAGE",
+	"PUBLICATION TABLE",
+	"PUBLICATION TABLES IN SCHEMA",
+	"ROW SECURITY",
+	"SECURITY LABEL",
+	"SEQUENCE OWNED BY",
+	"SEQUENCE SET",
+	"SHELL TYPE",
+	"TABLE ATTACH",
+	"TABLE DATA",
+	"TEXT SEARCH CONFIGURATION",
+	"TEXT SEARCH DICTIONARY",
+	"TEXT SEARCH PARSER",
+	"TEXT SEARCH TEMPLATE",
+	"USER MAPPING",
+}
+
+func init() {
+	sort.Slice(tocDescs, func(i, j int) bool { return len(tocDescs[i]) > len(tocDescs[j]) })
+}
+
+// ParseTOC reads the output of pg_restore --list into its header and entries
+func ParseTOC(r io.Reader) (types.TOCHeader, []types.TOCEntry, error) {
+	var header types.TOCHeader
+	var entries []types.TOCEntry
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, ";") {
+			parseHeaderLine(&header, strings.TrimSpace(strings.TrimPrefix(line, ";")))
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		entry, err := parseEntry(line)
+		if err != nil {
+			return header, nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return header, nil, fmt.Errorf("failed to read table of contents: %w", err)
+	}
+	return header, entries, nil
+}
+
+// parseHeaderLine picks archive metadata out of a "; key: value" comment
+func parseHeaderLine(header *types.TOCHeader, line string) {
+	// The creation time contains colons, so it is matched before splitting on one
+	if created, ok := strings.CutPrefix(line, "Archive created at "); ok {
+		header.CreatedAt = created
+		return
+	}
+	key, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return
+	}
+	value = strings.TrimSpace(value)
+	switch strings.TrimSpace(key) {
+	case "dbname":
+		header.Database = value
+	case "Format":
+		header.Format = value
+	case "Dumped from database version":
+		header.DumpedFrom = value
+	case "Dumped by pg_dump version":
+		header.DumpedBy = value
+	case "Compression":
+		header.Compression = value
+	case "Dump Version":
+		header.ArchiveVersion = value
+	}
+}
+
+// parseEntry splits "<id>; <oid> <oid> <desc> <namespace> <tag> <owner>" into its fields
+func parseEntry(line string) (types.TOCEntry, error) {
+	match := tocLinePattern.FindStringSubmatch(line)
+	if match == nil {
+		return types.TOCEntry{}, fmt.Errorf("unrecognized table of contents line %q", line)
+	}
+	id, _ := strconv.Atoi(match[1])
+	entry := types.TOCEntry{ID: id, Line: line}
+
+	rest := match[4]
+	for _, desc := range tocDescs {
+		if strings.HasPrefix(rest, desc+" ") {
+			entry.Desc = desc
+			break
+		}
+	}
+	if entry.Desc == "" {
+		entry.Desc, _, _ = strings.Cut(rest, " ")
+	}
+	rest = strings.TrimPrefix(rest[len(entry.Desc):], " ")
+
+	// The namespace is a single word ("-" when none); the owner is the last word and may be empty
+	namespace, rest, _ := strings.Cut(rest, " ")
+	if namespace != "-" {
+		entry.Namespace = namespace
+	}
+	if i := strings.LastIndex(rest, " "); i >= 0 {
+		entry.Tag, entry.Owner = rest[:i], rest[i+1:]
+	} else {
+		entry.Tag = rest
+	}
+	return entry, nil
+}
@@ -0,0 +1,161 @@
+package restore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// tocList is the output of pg_restore -l for a custom-format dump taken by pg_dump 16
+const tocList = `;
+; Archive created at 2024-05-10 14:32:11 -03
+;     dbname: shop
+;     TOC Entries: 42
+;     Compression: gzip
+;     Dump Version: 1.15-0
+;     Format: CUSTOM
+;     Integer: 4 bytes
+;     Offset: 8 bytes
+;     Dumped from database version: 16.2 (Debian 16.2-1.pgdg120+2)
+;     Dumped by pg_dump version: 16.2 (Debian 16.2-1.pgdg120+2)
+;
+;
+; Selected TOC Entries:
+;
+4; 2615 2200 SCHEMA - public pg_database_owner
+3420; 0 0 COMMENT - SCHEMA public pg_database_owner
+2; 3079 16384 EXTENSION - pgcrypto 
+3425; 0 0 COMMENT - EXTENSION pgcrypto 
+218; 1255 16390 FUNCTION public make_log() postgres
+216; 1259 16386 TABLE public customers postgres
+215; 1259 16385 SEQUENCE public customers_id_seq postgres
+3421; 0 0 SEQUENCE OWNED BY public customers_id_seq postgres
+3260; 2604 16389 DEFAULT public customers id postgres
+220; 1259 16402 MATERIALIZED VIEW public sales_summary postgres
+3412; 0 16386 TABLE DATA public customers postgres
+3422; 0 0 SEQUENCE SET public customers_id_seq postgres
+3418; 0 16402 MATERIALIZED VIEW DATA public sales_summary postgres
+3264; 2606 16392 CONSTRAINT public customers customers_pkey postgres
+3265; 1259 16393 INDEX public customers_name_idx postgres
+3266; 2606 16394 FK CONSTRAINT public orders orders_customer_fkey postgres
+3423; 0 0 ACL - SCHEMA public pg_database_owner
+2090; 826 16410 DEFAULT ACL public DEFAULT PRIVILEGES FOR TABLES postgres
+`
+
+func TestParseTOC(t *testing.T) {
+	header, entries, err := ParseTOC(strings.NewReader(tocList))
+	if err != nil {
+		t.Fatalf("ParseTOC: %v", err)
+	}
+
+	wantHeader := types.TOCHeader{
+		Database:       "shop",
+		Format:         "CUSTOM",
+		DumpedFrom:     "16.2 (Debian 16.2-1.pgdg120+2)",
+		DumpedBy:       "16.2 (Debian 16.2-1.pgdg120+2)",
+		CreatedAt:      "2024-05-10 14:32:11 -03",
+		Compression:    "gzip",
+		ArchiveVersion: "1.15-0",
+	}
+	if header != wantHeader {
+		t.Errorf("header = %+v, want %+v", header, wantHeader)
+	}
+
+	want := []struct {
+		id                          int
+		desc, namespace, tag, owner string
+	}{
+		{4, "SCHEMA", "", "public", "pg_database_owner"},
+		{3420, "COMMENT", "", "SCHEMA public", "pg_database_owner"},
+		{2, "EXTENSION", "", "pgcrypto", ""},
+		{3425, "COMMENT", "", "EXTENSION pgcrypto", ""},
+		{218, "FUNCTION", "public", "make_log()", "postgres"},
+		{216, "TABLE", "public", "customers", "postgres"},
+		{215, "SEQUENCE", "public", "customers_id_seq", "postgres"},
+		{3421, "SEQUENCE OWNED BY", "public", "customers_id_seq", "postgres"},
+		{3260, "DEFAULT", "public", "customers id", "postgres"},
+		{220, "MATERIALIZED VIEW", "public", "sales_summary", "postgres"},
+		{3412, "TABLE DATA", "public", "customers", "postgres"},
+		{3422, "SEQUENCE SET", "public", "customers_id_seq", "postgres"},
+		{3418, "MATERIALIZED VIEW DATA", "public", "sales_summary", "postgres"},
+		{3264, "CONSTRAINT", "public", "customers customers_pkey", "postgres"},
+		{3265, "INDEX", "public", "customers_name_idx", "postgres"},
+		{3266, "FK CONSTRAINT", "public", "orders orders_customer_fkey", "postgres"},
+		{3423, "ACL", "", "SCHEMA public", "pg_database_owner"},
+		{2090, "DEFAULT ACL", "public", "DEFAULT PRIVILEGES FOR TABLES", "postgres"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("parsed %d entries, want %d", len(entries), len(want))
+	}
+	lines := strings.Split(strings.TrimSpace(tocList[strings.Index(tocList, "4; "):]), "\n")
+	for i, w := range want {
+		e := entries[i]
+		if e.ID != w.id || e.Desc != w.desc || e.Namespace != w.namespace || e.Tag != w.tag || e.Owner != w.owner {
+			t.Errorf("entry %d = %d %q %q %q %q, want %d %q %q %q %q",
+				i, e.ID, e.Desc, e.Namespace, e.Tag, e.Owner, w.id, w.desc, w.namespace, w.tag, w.owner)
+		}
+		if strings.TrimRight(e.Line, " ") != strings.TrimRight(lines[i], " ") {
+			t.Errorf("entry %d line = %q, want %q", i, e.Line, lines[i])
+		}
+	}
+}
+
+func TestParseTOCLegacyHeader(t *testing.T) {
+	// pg_restore before 16 prints the zlib level as the compression
+	list := ";\n; Archive created at 2021-03-01 09:00:00 UTC\n;     dbname: legacy\n;     Compression: -1\n;     Format: DIRECTORY\n"
+	header, entries, err := ParseTOC(strings.NewReader(list))
+	if err != nil {
+		t.Fatalf("ParseTOC: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("parsed %d entries, want none", len(entries))
+	}
+	if header.Compression != "-1" || header.Format != "DIRECTORY" || header.Database != "legacy" || header.CreatedAt != "2021-03-01 09:00:00 UTC" {
+		t.Errorf("header = %+v", header)
+	}
+}
+
+func TestParseTOCInvalidLine(t *testing.T) {
+	if _, _, err := ParseTOC(strings.NewReader("pg_restore: error: input file is too short\n")); err == nil {
+		t.Error("expected an error for a line that is not a TOC entry")
+	}
+}
+
+func TestParseEntry(t *testing.T) {
+	tests := []struct {
+		line                        string
+		desc, namespace, tag, owner string
+	}{
+		{"5; 2615 16500 SCHEMA - Sales postgres", "SCHEMA", "", "Sales", "postgres"},
+		{"230; 1259 16510 TABLE Sales Order Items postgres", "TABLE", "Sales", "Order Items", "postgres"},
+		{"3300; 2606 16520 CONSTRAINT Sales Order Items Order Items_pkey postgres", "CONSTRAINT", "Sales", "Order Items Order Items_pkey", "postgres"},
+		{"3301; 0 0 TABLE ATTACH public measurement_y2024 postgres", "TABLE ATTACH", "public", "measurement_y2024", "postgres"},
+		{"3302; 0 0 INDEX ATTACH public measurement_y2024_logdate_idx postgres", "INDEX ATTACH", "public", "measurement_y2024_logdate_idx", "postgres"},
+		{"3303; 2613 16600 LARGE OBJECT - 16600 postgres", "LARGE OBJECT", "", "16600", "postgres"},
+		{"3304; 0 0 BLOB METADATA - 16600..16610 postgres", "BLOB METADATA", "", "16600..16610", "postgres"},
+		{"3305; 3466 16700 EVENT TRIGGER - audit_ddl postgres", "EVENT TRIGGER", "", "audit_ddl", "postgres"},
+		{"3306; 3600 16710 TEXT SEARCH DICTIONARY public portuguese_unaccent postgres", "TEXT SEARCH DICTIONARY", "public", "portuguese_unaccent", "postgres"},
+		{"3307; 6106 16720 PUBLICATION TABLE public pub_orders orders", "PUBLICATION TABLE", "public", "pub_orders", "orders"},
+		{"3308; 0 0 DATABASE PROPERTIES - shop postgres", "DATABASE PROPERTIES", "", "shop", "postgres"},
+		{"3309; 1247 16730 SHELL TYPE public money2 postgres", "SHELL TYPE", "public", "money2", "postgres"},
+		{"3310; 0 0 ROW SECURITY public accounts postgres", "ROW SECURITY", "public", "accounts", "postgres"},
+	}
+	for _, tt := range tests {
+		e, err := parseEntry(tt.line)
+		if err != nil {
+			t.Errorf("parseEntry(%q): %v", tt.line, err)
+			continue
+		}
+		if e.Desc != tt.desc || e.Namespace != tt.namespace || e.Tag != tt.tag || e.Owner != tt.owner || e.Line != tt.line {
+			t.Errorf("parseEntry(%q) = %q %q %q %q, want %q %q %q %q",
+				tt.line, e.Desc, e.Namespace, e.Tag, e.Owner, tt.desc, tt.namespace, tt.tag, tt.owner)
+		}
+	}
+
+	for _, line := range []string{"", "; comment", "12 2615 2200 SCHEMA - public postgres", "x; 0 0 TABLE public t postgres"} {
+		if _, err := parseEntry(line); err == nil {
+			t.Errorf("parseEntry(%q) accepted an invalid line", line)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package restore
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// Object categories of the restore tree, in display order
+const (
+	KindTable    = "tabela"
+	KindView     = "view"
+	KindSequence = "sequência"
+	KindFunction = "função"
+	KindIndex    = "índice"
+	KindType     = "tipo"
+	KindOther    = "outro"
+)
+
+// kindOrder sorts the objects of a schema by category
+var kindOrder = map[string]int{
+	KindTable: 0, KindView: 1, KindSequence: 2, KindFunction: 3, KindIndex: 4, KindType: 5, KindOther: 6,
+}
+
+// objectKinds maps TOC object types that define a selectable object to their category
+var objectKinds = map[string]string{
+	"TABLE":             KindTable,
+	"FOREIGN TABLE":     KindTable,
+	"VIEW":              KindView,
+	"MATERIALIZED VIEW": KindView,
+	"SEQUENCE":          KindSequence,
+	"FUNCTION":          KindFunction,
+	"PROCEDURE":         KindFunction,
+	"AGGREGATE":         KindFunction,
+	"INDEX":             KindIndex,
+	"TYPE":              KindType,
+	"DOMAIN":            KindType,
+}
+
+// globalSchema labels the row grouping entries outside any schema
+const globalSchema = "(objetos globais)"
+
+// BuildTree groups TOC entries into schema rows followed by their objects. Data, constraints,
+// triggers, defaults, comments and privileges are attached to the object they belong to.
+func BuildTree(entries []types.TOCEntry) []types.RestoreNode {
+	type object struct {
+		node  types.RestoreNode
+		order int
+	}
+	type schema struct {
+		entries []int
+		objects []*object
+		byName  map[string]*object
+	}
+
+	schemas := make(map[string]*schema)
+	var schemaOrder []string
+	schemaOf := func(name string) *schema {
+		if name == "" {
+			name = globalSchema
+		}
+		s, ok := schemas[name]
+		if !ok {
+			s = &schema{byName: make(map[string]*object)}
+			schemas[name] = s
+			schemaOrder = append(schemaOrder, name)
+		}
+		return s
+	}
+	addObject := func(s *schema, kind, name string, i int) {
+		o := &object{node: types.RestoreNode{Label: name, Kind: kind, Entries: []int{i}}, order: i}
+		s.objects = append(s.objects, o)
+		if _, exists := s.byName[name]; !exists {
+			s.byName[name] = o
+		}
+	}
+	// addOther lists an entry of a type without its own category; comments and privileges
+	// tagged "<TYPE> <name>" find it by that key
+	addOther := func(s *schema, e types.TOCEntry, i int) {
+		addObject(s, KindOther, strings.ToLower(e.Desc)+" "+e.Tag, i)
+		s.byName[e.Desc+" "+e.Tag] = s.objects[len(s.objects)-1]
+	}
+
+	// First pass: objects that can be selected on their own
+	var rest []int
+	for i, e := range entries {
+		switch kind, ok := objectKinds[e.Desc]; {
+		case e.Desc == "SCHEMA":
+			s := schemaOf(e.Tag)
+			s.entries = append(s.entries, i)
+		case ok && e.Namespace != "":
+			addObject(schemaOf(e.Namespace), kind, e.Tag, i)
+		default:
+			rest = append(rest, i)
+		}
+	}
+
+	// Second pass: attach dependent entries to their object, or list them on their own
+	for _, i := range rest {
+		e := entries[i]
+		if e.Namespace == "" {
+			if target, ok := describedSchema(e); ok {
+				if s, exists := schemas[target]; exists {
+					s.entries = append(s.entries, i)
+					continue
+				}
+			}
+		}
+
+		s := schemaOf(e.Namespace)
+		if o, ok := s.byName[ownerObject(e)]; ok {
+			o.node.Entries = append(o.node.Entries, i)
+			continue
+		}
+		if o, ok := s.byName[e.Tag]; ok && describesObject(e) {
+			o.node.Entries = append(o.node.Entries, i)
+			continue
+		}
+		addOther(s, e, i)
+	}
+
+	var nodes []types.RestoreNode
+	for _, name := range schemaOrder {
+		s := schemas[name]
+		parent := len(nodes)
+		nodes = append(nodes, types.RestoreNode{Label: name, Parent: -1, Entries: s.entries})
+
+		sort.SliceStable(s.objects, func(i, j int) bool {
+			a, b := s.objects[i], s.objects[j]
+			if kindOrder[a.node.Kind] != kindOrder[b.node.Kind] {
+				return kindOrder[a.node.Kind] < kindOrder[b.node.Kind]
+			}
+			return a.order < b.order
+		})
+		for _, o := range s.objects {
+			o.node.Parent = parent
+			nodes = append(nodes, o.node)
+		}
+	}
+	return nodes
+}
+
+// ownerObject returns the name of the object a dependent entry belongs to
+func ownerObject(e types.TOCEntry) string {
+	switch e.Desc {
+	case "TABLE DATA", "MATERIALIZED VIEW DATA", "SEQUENCE SET", "SEQUENCE OWNED BY", "ROW SECURITY":
+		return e.Tag
+	case "CONSTRAINT", "FK CONSTRAINT", "TRIGGER", "DEFAULT", "POLICY", "RULE":
+		// Tagged "<table> <name>"
+		table, _, _ := strings.Cut(e.Tag, " ")
+		return table
+	case "COMMENT", "ACL", "SECURITY LABEL":
+		// Tagged "<TYPE> <name>", or "COLUMN <table>.<column>"
+		if column, ok := strings.CutPrefix(e.Tag, "COLUMN "); ok {
+			table, _, _ := strings.Cut(column, ".")
+			return table
+		}
+		for desc := range objectKinds {
+			if name, ok := strings.CutPrefix(e.Tag, desc+" "); ok {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// describesObject reports whether e is a comment, privilege or label on another object
+func describesObject(e types.TOCEntry) bool {
+	switch e.Desc {
+	case "COMMENT", "ACL", "SECURITY LABEL":
+		return true
+	}
+	return false
+}
+
+// describedSchema returns the schema a namespace-less COMMENT or ACL entry is about
+func describedSchema(e types.TOCEntry) (string, bool) {
+	if !describesObject(e) {
+		return "", false
+	}
+	return strings.CutPrefix(e.Tag, "SCHEMA ")
+}
+
+// SelectedEntries returns the TOC entries of the chosen rows, in archive order
+func SelectedEntries(nodes []types.RestoreNode, choices map[int]bool) []int {
+	seen := make(map[int]bool)
+	var selected []int
+	for i, node := range nodes {
+		if !choices[i] {
+			continue
+		}
+		for _, e := range node.Entries {
+			if !seen[e] {
+				seen[e] = true
+				selected = append(selected, e)
+			}
+		}
+	}
+	sort.Ints(selected)
+	return selected
+}
@@ -0,0 +1,151 @@
+package tunnel
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/logging"
+)
+
+// readyPollInterval is how often the local end of the tunnel is probed while it starts
+const readyPollInterval = 100 * time.Millisecond
+
+// Service manages an SSH port-forward to a database behind a bastion host
+type Service struct {
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	done   chan struct{}
+	remote string
+}
+
+// NewService creates a new tunnel service
+func NewService(logger *slog.Logger) *Service {
+	return &Service{logger: logger}
+}
+
+// Open starts an ssh port-forward from a free local port to remoteHost:remotePort through the
+// bastion, replacing any tunnel already open, and returns the local port
+func (s *Service) Open(ctx context.Context, settings config.SSHSettings, remoteHost, remotePort string) (string, error) {
+	s.Close()
+
+	sshPath, err := exec.LookPath("ssh")
+	if err != nil {
+		return "", fmt.Errorf("ssh client not found in PATH: %w", err)
+	}
+
+	localPort, err := freePort()
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{
+		"-N",
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "BatchMode=yes",
+		"-o", "ServerAliveInterval=30",
+		"-L", fmt.Sprintf("127.0.0.1:%s:%s", localPort, net.JoinHostPort(remoteHost, remotePort)),
+	}
+	if settings.Port != "" {
+		args = append(args, "-p", settings.Port)
+	}
+	if settings.KeyFile != "" {
+		args = append(args, "-i", settings.KeyFile, "-o", "IdentitiesOnly=yes")
+	}
+	if settings.KnownHosts != "" {
+		args = append(args, "-o", "UserKnownHostsFile="+settings.KnownHosts, "-o", "StrictHostKeyChecking=yes")
+	}
+	target := settings.Host
+	if settings.User != "" {
+		target = settings.User + "@" + settings.Host
+	}
+	args = append(args, target)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(sshPath, args...)
+	cmd.Stderr = &stderr
+
+	logger := s.logger.With("bastion", settings.Host, "remote", net.JoinHostPort(remoteHost, remotePort), "local_port", localPort)
+	logger.Info("opening ssh tunnel", "command", logging.RedactArgs(sshPath, args))
+
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to start ssh: %w", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+
+	// Wait until the forward accepts connections, ssh exits or ctx ends
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			logger.Error("ssh tunnel failed", "stderr", stderr.String())
+			return "", fmt.Errorf("ssh tunnel to %s failed: %s", settings.Host, bytes.TrimSpace(stderr.Bytes()))
+		case <-ctx.Done():
+			cmd.Process.Kill()
+			<-done
+			return "", ctx.Err()
+		case <-ticker.C:
+			probe, err := net.DialTimeout("tcp", "127.0.0.1:"+localPort, readyPollInterval)
+			if err != nil {
+				continue
+			}
+			probe.Close()
+
+			s.mu.Lock()
+			s.cmd, s.done, s.remote = cmd, done, net.JoinHostPort(remoteHost, remotePort)
+			s.mu.Unlock()
+
+			logger.Info("ssh tunnel ready")
+			return localPort, nil
+		}
+	}
+}
+
+// Close tears down the active tunnel, if any
+func (s *Service) Close() error {
+	s.mu.Lock()
+	cmd, done, remote := s.cmd, s.done, s.remote
+	s.cmd, s.done, s.remote = nil, nil, ""
+	s.mu.Unlock()
+
+	if cmd == nil {
+		return nil
+	}
+
+	s.logger.Info("closing ssh tunnel", "remote", remote)
+	if err := cmd.Process.Kill(); err != nil {
+		select {
+		case <-done:
+			return nil // already exited
+		default:
+			return fmt.Errorf("failed to stop ssh tunnel: %w", err)
+		}
+	}
+	<-done
+	return nil
+}
+
+// freePort asks the kernel for an unused local TCP port
+func freePort() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("failed to find a free local port: %w", err)
+	}
+	defer listener.Close()
+	return strconv.Itoa(listener.Addr().(*net.TCPAddr).Port), nil
+}
@@ -1,6 +1,10 @@
 package types
 
 import (
+	"io"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
 	"github.com/charmbracelet/bubbles/paginator"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -14,15 +18,242 @@ const (
 	ScreenConnection
 	ScreenBackupList
 	ScreenBackupProgress
+	ScreenBackupConfirm
+	ScreenProfileSelect
+	ScreenMultiBackupList
+	ScreenTableList
+	ScreenTableExport
+	ScreenRestoreFile
+	ScreenRestoreTOC
+	ScreenRestoreConfirm
+	ScreenRestoreProgress
+	ScreenCopySetup
+	ScreenCopyConfirm
+	ScreenCopyProgress
+	ScreenCompareSelect
+	ScreenCompareResult
+	ScreenInspect
+	ScreenPhysicalConfirm
+	ScreenPhysicalProgress
+	ScreenPITRSetup
+	ScreenPITRResult
+	ScreenSchemaList
+)
+
+// Connection form fields, in display order
+const (
+	InputHost = iota
+	InputPort
+	InputUser
+	InputPassword
+	InputDatabase
+	InputCount
 )
 
 // BackupCompleteMsg represents a completed backup operation
 type BackupCompleteMsg struct {
+	Success    int
+	Errors     []string
+	Filenames  []string
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// BackupPreflight represents the pre-flight estimates shown before a backup run
+type BackupPreflight struct {
+	Databases         int
+	UnknownSizes      int
+	SourceSize        int64
+	EstimatedSize     int64
+	FreeSpace         int64 // -1 when unknown
+	EstimatedDuration time.Duration
+	Destination       string
+	ServerVersion     string
+	PgDump            string // pg_dump selected for the server version, empty when none is compatible
+	Blocked           bool
+	OutOfSpace        bool // the estimated output exceeds the free space; blocks exports too
+	Warning           string
+}
+
+// DatabasesLoadedMsg represents the result of an asynchronous connection attempt
+type DatabasesLoadedMsg struct {
+	Attempt       int
+	Databases     []DatabaseInfo
+	TunnelPort    string
+	ServerVersion int
+	Err           error
+}
+
+// TablesLoadedMsg represents the tables listed for the per-table export screen
+type TablesLoadedMsg struct {
+	Database string
+	Tables   []TableInfo
+	Err      error
+}
+
+// SchemasLoadedMsg represents the schemas listed for the per-schema backup screen
+type SchemasLoadedMsg struct {
+	Database string
+	Schemas  []SchemaInfo
+	Err      error
+}
+
+// TableExportCompleteMsg represents the result of a per-table export
+type TableExportCompleteMsg struct {
+	Files  []string
+	Rows   int64
+	Errors []string
+}
+
+// RestoreTOCLoadedMsg represents the table of contents read from an archive
+type RestoreTOCLoadedMsg struct {
+	File    string
+	Header  TOCHeader
+	Entries []TOCEntry
+	Err     error
+}
+
+// RestoreProgressMsg reports the progress of a running restore
+type RestoreProgressMsg struct {
+	SafetyBackup bool // the safety backup of the target is being taken
+	Done         int  // TOC entries restored
+	Total        int
+	Current      string // object being restored, e.g. "TABLE DATA public.users"
+}
+
+// DatabaseStatusMsg represents whether a database exists and how many sessions use it
+type DatabaseStatusMsg struct {
+	Database    string
+	Exists      bool
+	Connections int
+	Err         error
+}
+
+// RestoreCompleteMsg represents the result of a pg_restore run
+type RestoreCompleteMsg struct {
+	Target       string
+	Entries      int
+	SafetyBackup string // file written before restoring over an existing database
+	Warnings     string // pg_restore messages of a successful run
+	Err          error
+	StartedAt    time.Time
+	FinishedAt   time.Time
+}
+
+// CopyPlanMsg represents the checks made on the target before copying a database
+type CopyPlanMsg struct {
+	Profile    string
+	Target     DatabaseConnection
+	Database   string
+	Exists     bool   // the target database already exists
+	Production bool   // the target profile forbids restores
+	PgDump     string // client reading the source
+	Loader     string // pg_restore or psql writing the target
+	Warning    string // why no suitable client was found
+	Err        error
+}
+
+// CopyProgressMsg reports the bytes streamed by a running database copy
+type CopyProgressMsg struct {
+	Bytes int64
+}
+
+// CopyCompleteMsg represents the result of a database copy
+type CopyCompleteMsg struct {
+	Source     string
+	Target     string
+	Profile    string
+	Bytes      int64
+	Warnings   string
+	Err        error
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// PhysicalPlanMsg represents the checks made before a base backup
+type PhysicalPlanMsg struct {
+	Client        string
+	Dir           string
+	WALArchiveDir string
+	Keep          int
+	Err           error
+}
+
+// PhysicalProgressMsg reports the progress of a running base backup, as estimated by pg_basebackup
+type PhysicalProgressMsg struct {
+	DoneKB  int64
+	TotalKB int64
+}
+
+// PhysicalCompleteMsg represents the result of a base backup
+type PhysicalCompleteMsg struct {
+	Path       string
+	Size       int64
+	Warnings   string
+	Removed    []string // base backups removed by retention
+	RemovedWAL int      // archived WAL files removed by retention
+	Err        error
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// PITRCompleteMsg represents a data directory prepared for point-in-time recovery
+type PITRCompleteMsg struct {
+	Backup        string
+	DataDir       string
+	WALArchiveDir string
+	Target        string // recovery target setting written, empty to replay all archived WAL
+	Version       string // PostgreSQL major version of the base backup
+	StartWAL      string // first WAL segment needed by the recovery
+	ArchivedWAL   int    // archived segments from StartWAL on
+	Err           error
+}
+
+// SchemaCompareMsg carries the differences found between two schemas
+type SchemaCompareMsg struct {
+	Left    string
+	Right   string
+	Changes []SchemaChange
+	Err     error
+}
+
+// BackupTarget represents a database selected for a multi-server backup run
+type BackupTarget struct {
+	Profile    string
+	Connection DatabaseConnection
+	Database   string
+	SourceSize int64
+	PgDump     config.PgDumpSettings
+}
+
+// HostResult represents the outcome of a multi-server run for one profile
+type HostResult struct {
+	Profile   string
+	Host      string
 	Success   int
 	Errors    []string
 	Filenames []string
 }
 
+// MultiBackupCompleteMsg represents a completed multi-server backup run
+type MultiBackupCompleteMsg struct {
+	Results    []HostResult
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// ProfilesLoadedMsg represents the databases listed from several profiles
+type ProfilesLoadedMsg struct {
+	Targets  []BackupTarget
+	Errors   []string
+	Sessions []io.Closer // tunnels that must stay open for the run
+}
+
+// NotificationSentMsg represents the result of dispatching backup notifications
+type NotificationSentMsg struct {
+	Errors []string
+}
+
 // Model represents the application state
 type Model struct {
 	// Screen navigation
@@ -36,15 +267,31 @@ type Model struct {
 	Databases         []string
 	FilteredDatabases []string
 	Choices           map[int]string
+	DatabaseInfo      map[string]DatabaseInfo
+	SortBy            int
 
 	// Connection details
-	DbHost     string
-	DbPort     string
-	DbUser     string
-	DbPassword string
-	DbName     string
-	InputField int
-	Inputs     []string
+	ProfileName    string
+	DbHost         string
+	DbPort         string
+	DbUser         string
+	DbPassword     string
+	DbName         string
+	SSLMode        string
+	ConnectTimeout time.Duration
+	SSHBastion     string
+	PgDump         config.PgDumpSettings
+	Production     bool // restores are blocked unless AllowRestore is set
+	AllowRestore   bool
+	TunnelPort     string
+	ServerVersion  int
+	InputField     int
+	Inputs         []textinput.Model
+
+	// Connection string input mode
+	URIMode     bool
+	URIInput    textinput.Model
+	ExportedURI string
 
 	// UI components
 	Spinner     spinner.Model
@@ -54,21 +301,281 @@ type Model struct {
 
 	// Connection status
 	ConnectionError string
+	Connecting      bool
+	ConnectAttempt  int
 
 	// Backup status
+	Preflight       BackupPreflight
+	Masking         string // masking profile applied by logical exports, empty for none
+	MaskingProfiles []string
 	BackupCompleted bool
 	BackupErrors    []string
 	BackupSuccess   int
 	BackupFilenames []string
 	TotalBackups    int
 	IsProcessing    bool
+
+	// Multi-server backup
+	ProfileNames     []string
+	SelectedProfiles map[int]bool
+	MultiTargets     []BackupTarget
+	MultiChoices     map[int]bool
+	MultiErrors      []string
+	MultiResults     []HostResult
+	MultiRun         bool
+	LoadingProfiles  bool
+
+	// Per-table export
+	TableDatabase string
+	Tables        []TableInfo
+	TableCursor   int
+	TableChoices  map[int]bool
+	LoadingTables bool
+	TableError    string
+	ExportFormat  string
+	ExportField   int // 0 format, 1 WHERE filter, 2 row limit
+	ExportWhere   textinput.Model
+	ExportLimit   textinput.Model
+	ExportError   string
+	Exporting     bool
+	ExportResult  *TableExportCompleteMsg
+
+	// Per-schema backup
+	SchemaDatabase string
+	Schemas        []SchemaInfo
+	SchemaCursor   int
+	SchemaChoices  map[int]bool
+	LoadingSchemas bool
+	SchemaError    string
+	BackupSchemas  []SchemaInfo // schemas awaiting confirmation, nil for a database run
+
+	// Restore
+	RestoreArchives  []ArchiveFile
+	RestoreFile      string
+	RestorePathMode  bool
+	RestorePathInput textinput.Model
+	RestoreHeader    TOCHeader
+	RestoreTOC       []TOCEntry
+	RestoreNodes     []RestoreNode
+	RestoreCursor    int
+	RestoreChoices   map[int]bool
+	RestoreLoading   bool
+	Inspecting       bool // the archive list opens the inspector instead of the restore tree
+	RestoreError     string
+	RestoreTarget    textinput.Model
+	RestoreStatus    *DatabaseStatusMsg // target database state, nil until checked
+	RestoreChecking  bool
+	RestoreField     int
+	RestoreSafety    bool // dump the existing target before restoring
+	RestoreClean     bool // drop existing objects first (--clean --if-exists)
+	RestoreConfirm   textinput.Model
+	RestoreJobs      int
+	Restoring        bool
+	RestoreProgress  RestoreProgressMsg
+	RestoreStarted   time.Time
+	RestoreResult    *RestoreCompleteMsg
+
+	// Database copy
+	CopyProfiles []string
+	CopySource   int // index into Databases
+	CopyProfile  int // index into CopyProfiles
+	CopyField    int
+	CopyTarget   textinput.Model
+	CopyChecking bool
+	CopyError    string
+	CopyPlan     *CopyPlanMsg
+	Copying      bool
+	CopyProgress CopyProgressMsg
+	CopyStarted  time.Time
+	CopyResult   *CopyCompleteMsg
+
+	// Physical backup
+	PhysicalChecking bool
+	PhysicalPlan     *PhysicalPlanMsg
+	PhysicalRunning  bool
+	PhysicalProgress PhysicalProgressMsg
+	PhysicalStarted  time.Time
+	PhysicalResult   *PhysicalCompleteMsg
+
+	// Point-in-time recovery
+	PITRBackups []BaseBackup
+	PITRBackup  int // index into PITRBackups
+	PITRField   int
+	PITRTarget  textinput.Model
+	PITRDataDir textinput.Model
+	PITRWALDir  textinput.Model
+	PITRRunning bool
+	PITRError   string
+	PITRResult  *PITRCompleteMsg
+
+	// Archive inspector
+	InspectSize      int64
+	InspectCounts    []ObjectCount
+	InspectSearch    textinput.Model
+	InspectSearching bool
+	InspectMatches   []int // indexes into RestoreTOC matching the search
+	InspectCursor    int
+
+	// Schema comparison
+	CompareSources []CompareSource
+	CompareCursor  int
+	CompareLeft    int // index of the first chosen source, -1 until chosen
+	CompareLoading bool
+	CompareError   string
+	CompareResult  *SchemaCompareMsg
+	CompareLines   []string
+	CompareOffset  int
+	CompareStatus  string
+
+	// Notification status
+	NotificationErrors []string
+}
+
+// DatabaseInfo represents a database and its metadata
+type DatabaseInfo struct {
+	Name        string
+	Size        int64 // -1 when the size is not readable
+	Owner       string
+	Encoding    string
+	Connections int
+	LastBackup  time.Time
+}
+
+// SchemaInfo represents a user schema of a database
+type SchemaInfo struct {
+	Name   string
+	Owner  string
+	Tables int
+	Size   int64 // tables and materialized views with their indexes
+}
+
+// TableInfo represents a table or view of a database
+type TableInfo struct {
+	Schema string
+	Name   string
+	Kind   string // pg_class.relkind: r, p, v, m or f
+	Rows   int64  // planner estimate, -1 when unknown
+	Size   int64  // -1 for views and foreign tables
+}
+
+// QualifiedName returns schema.name
+func (t TableInfo) QualifiedName() string {
+	return t.Schema + "." + t.Name
+}
+
+// ArchiveFile represents a backup file that can be restored
+type ArchiveFile struct {
+	Path      string
+	Database  string
+	Schema    string // set for backups of a single schema
+	Profile   string
+	CreatedAt time.Time
+	Size      int64
+}
+
+// CompareSource is one side of a schema comparison: a backup file or a live database
+type CompareSource struct {
+	Label     string
+	Path      string // backup file, empty for a live database
+	Kind      string // catalog kind of the file
+	Database  string
+	CreatedAt time.Time
+}
+
+// BaseBackup is a physical backup recorded in the catalog
+type BaseBackup struct {
+	Profile    string
+	Host       string
+	Port       string
+	Path       string
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// SchemaChange is one difference between two schemas
+type SchemaChange struct {
+	Change string // +, - or ~
+	Object string // table, column, constraint or index
+	Table  string
+	Name   string
+	Old    string
+	New    string
+}
+
+// TOCHeader holds the archive metadata printed by pg_restore --list
+type TOCHeader struct {
+	Database       string
+	Format         string
+	DumpedFrom     string // server version
+	DumpedBy       string // pg_dump version
+	CreatedAt      string // as printed by pg_restore, with the dump's time zone
+	Compression    string // algorithm name, or a zlib level (-1 default, 0 none) before PostgreSQL 16
+	ArchiveVersion string
+}
+
+// ObjectCount is the number of archive entries of one object type
+type ObjectCount struct {
+	Desc  string
+	Count int
+}
+
+// TOCEntry represents one line of a pg_restore --list table of contents
+type TOCEntry struct {
+	ID        int
+	Desc      string // object type, e.g. TABLE, TABLE DATA, INDEX
+	Namespace string // empty for objects outside a schema
+	Tag       string
+	Owner     string
+	Line      string // original line, written back to the --use-list file
+}
+
+// RestoreNode represents a row of the restore tree: a schema or one of its objects
+type RestoreNode struct {
+	Label   string
+	Kind    string // object category, empty for schema rows
+	Parent  int    // index of the schema row, -1 for schema rows
+	Entries []int  // indexes of the TOC entries restored with this row
+}
+
+// Database list sort orders
+const (
+	SortByName = iota
+	SortBySize
+	SortByLastBackup
+)
+
+// Connection returns the connection parameters entered in the connection form
+func (m Model) Connection() DatabaseConnection {
+	return DatabaseConnection{
+		Host:           m.Inputs[InputHost].Value(),
+		Port:           m.Inputs[InputPort].Value(),
+		User:           m.Inputs[InputUser].Value(),
+		Password:       m.Inputs[InputPassword].Value(),
+		Database:       m.Inputs[InputDatabase].Value(),
+		SSLMode:        m.SSLMode,
+		ConnectTimeout: m.ConnectTimeout,
+		TunnelPort:     m.TunnelPort,
+		ServerVersion:  m.ServerVersion,
+	}
 }
 
 // DatabaseConnection represents database connection parameters
 type DatabaseConnection struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	Database string
+	Host           string
+	Port           string
+	User           string
+	Password       string
+	Database       string
+	SSLMode        string
+	ConnectTimeout time.Duration
+	TunnelPort     string // local port of an SSH tunnel to Host:Port, if any
+	ServerVersion  int    // server_version_num reported after connecting, 0 when unknown
+}
+
+// Address returns the host and port to dial, going through the SSH tunnel when one is open
+func (c DatabaseConnection) Address() (string, string) {
+	if c.TunnelPort != "" {
+		return "127.0.0.1", c.TunnelPort
+	}
+	return c.Host, c.Port
 }
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"path/filepath"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/compare"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// comparePageSize is the number of diff lines scrolled by page up and page down
+const comparePageSize = 15
+
+// openCompare lists the live databases and catalog backups that can be compared
+func (a *App) openCompare() (tea.Model, tea.Cmd) {
+	var databases []string
+	if len(a.model.Databases) > 1 {
+		databases = a.model.Databases[1:] // skip "All Databases"
+	}
+	a.model.CompareSources = a.compareService.Sources(databases)
+	if len(a.model.CompareSources) < 2 {
+		return a, nil
+	}
+	a.model.CompareCursor = 0
+	a.model.CompareLeft = -1
+	a.model.CompareError = ""
+	a.model.CompareResult = nil
+	a.model.Screen = types.ScreenCompareSelect
+	return a, nil
+}
+
+// handleCompareSelectKeys picks the two sides of a comparison: the first Enter chooses A, the second B
+func (a *App) handleCompareSelectKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if a.model.CompareLoading {
+		if msg.String() == "ctrl+c" {
+			return a, tea.Quit
+		}
+		return a, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return a, tea.Quit
+	case "esc":
+		// Undo the first choice before leaving
+		if a.model.CompareLeft >= 0 {
+			a.model.CompareLeft = -1
+			return a, nil
+		}
+		a.model.Screen = types.ScreenMenu
+	case "up", "k":
+		if a.model.CompareCursor > 0 {
+			a.model.CompareCursor--
+		}
+	case "down", "j":
+		if a.model.CompareCursor < len(a.model.CompareSources)-1 {
+			a.model.CompareCursor++
+		}
+	case "enter":
+		if a.model.CompareLeft < 0 {
+			a.model.CompareLeft = a.model.CompareCursor
+			return a, nil
+		}
+		if a.model.CompareCursor == a.model.CompareLeft {
+			return a, nil
+		}
+		left := a.model.CompareSources[a.model.CompareLeft]
+		right := a.model.CompareSources[a.model.CompareCursor]
+		a.model.CompareLoading = true
+		a.model.CompareError = ""
+		return a, tea.Batch(a.model.Spinner.Tick,
+			a.compareService.CompareCmd(left, right, a.model.PgDump, a.model.ServerVersion))
+	}
+	return a, nil
+}
+
+// handleSchemaCompare shows the differences once both schemas are read
+func (a *App) handleSchemaCompare(msg types.SchemaCompareMsg) (tea.Model, tea.Cmd) {
+	a.model.CompareLoading = false
+	if msg.Err != nil {
+		a.model.CompareError = msg.Err.Error()
+		return a, nil
+	}
+
+	a.model.CompareResult = &msg
+	a.model.CompareLines = compare.Lines(msg.Changes)
+	a.model.CompareOffset = 0
+	a.model.CompareStatus = ""
+	a.model.Screen = types.ScreenCompareResult
+	return a, nil
+}
+
+// handleCompareResultKeys scrolls the differences and exports them as text
+func (a *App) handleCompareResultKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	last := max(0, len(a.model.CompareLines)-comparePageSize)
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return a, tea.Quit
+	case "esc":
+		a.model.CompareLeft = -1
+		a.model.Screen = types.ScreenCompareSelect
+	case "up", "k":
+		a.model.CompareOffset = max(0, a.model.CompareOffset-1)
+	case "down", "j":
+		a.model.CompareOffset = min(last, a.model.CompareOffset+1)
+	case "pgup":
+		a.model.CompareOffset = max(0, a.model.CompareOffset-comparePageSize)
+	case "pgdown", " ":
+		a.model.CompareOffset = min(last, a.model.CompareOffset+comparePageSize)
+	case "home", "g":
+		a.model.CompareOffset = 0
+	case "end", "G":
+		a.model.CompareOffset = last
+	case "e":
+		dir, err := a.backupService.BackupDir()
+		if err == nil {
+			var path string
+			path, err = compare.WriteText(dir, *a.model.CompareResult)
+			if err == nil {
+				a.model.CompareStatus = "Comparação salva em " + filepath.Base(path)
+			}
+		}
+		if err != nil {
+			a.model.CompareStatus = "Erro: " + err.Error()
+		}
+	}
+	return a, nil
+}
@@ -0,0 +1,353 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// Fields of the database copy form
+const (
+	copyFieldSource = iota
+	copyFieldProfile
+	copyFieldTarget
+	copyFieldCount
+)
+
+// copyTargetSuffix is appended to the source name to suggest the target database
+const copyTargetSuffix = "_copy"
+
+// newCopyInput creates the target database input of the copy form
+func newCopyInput() textinput.Model {
+	target := textinput.New()
+	target.Prompt = ""
+	target.Placeholder = "banco de destino"
+	target.CharLimit = 63
+	target.Width = 40
+	return target
+}
+
+// openCopy starts the copy form with the first database of the connected server
+func (a *App) openCopy() (tea.Model, tea.Cmd) {
+	if len(a.model.Databases) < 2 { // only "All Databases"
+		return a, nil
+	}
+
+	a.model.CopyProfiles = a.model.ProfileNames
+	if len(a.model.CopyProfiles) == 0 {
+		a.model.CopyProfiles = []string{a.profile.Name}
+	}
+	a.model.CopyProfile = 0
+	a.model.CopySource = 1
+	a.model.CopyTarget.SetValue(a.model.Databases[1] + copyTargetSuffix)
+	a.model.CopyError = ""
+	a.model.CopyPlan = nil
+	a.model.CopyResult = nil
+	a.model.CopyField = copyFieldSource
+	a.focusCopyField()
+	a.model.Screen = types.ScreenCopySetup
+	return a, nil
+}
+
+// handleCopySetupKeys processes keys for the database copy form
+func (a *App) handleCopySetupKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if a.model.CopyChecking {
+		if msg.String() == "ctrl+c" {
+			return a, tea.Quit
+		}
+		return a, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return a, tea.Quit
+	case "esc":
+		a.model.CopyTarget.Blur()
+		a.model.Screen = types.ScreenMenu
+		return a, nil
+	case "tab", "down":
+		a.model.CopyField = (a.model.CopyField + 1) % copyFieldCount
+		a.focusCopyField()
+		return a, nil
+	case "shift+tab", "up":
+		a.model.CopyField = (a.model.CopyField + copyFieldCount - 1) % copyFieldCount
+		a.focusCopyField()
+		return a, nil
+	case "enter":
+		return a.planCopy()
+	}
+
+	var cmd tea.Cmd
+	switch a.model.CopyField {
+	case copyFieldSource:
+		switch msg.String() {
+		case "left", "h":
+			a.cycleCopySource(-1)
+		case "right", "l", " ":
+			a.cycleCopySource(1)
+		}
+	case copyFieldProfile:
+		switch msg.String() {
+		case "left", "h":
+			a.model.CopyProfile = (a.model.CopyProfile + len(a.model.CopyProfiles) - 1) % len(a.model.CopyProfiles)
+		case "right", "l", " ":
+			a.model.CopyProfile = (a.model.CopyProfile + 1) % len(a.model.CopyProfiles)
+		}
+	case copyFieldTarget:
+		a.model.CopyTarget, cmd = a.model.CopyTarget.Update(msg)
+	}
+	return a, cmd
+}
+
+// cycleCopySource selects another source database, updating the suggested target name
+func (a *App) cycleCopySource(delta int) {
+	n := len(a.model.Databases) - 1 // without "All Databases"
+	previous := a.model.Databases[a.model.CopySource]
+	a.model.CopySource = (a.model.CopySource-1+delta+n)%n + 1
+	if a.model.CopyTarget.Value() == previous+copyTargetSuffix {
+		a.model.CopyTarget.SetValue(a.model.Databases[a.model.CopySource] + copyTargetSuffix)
+	}
+}
+
+// focusCopyField focuses the target input when it is the active copy form field
+func (a *App) focusCopyField() {
+	a.model.CopyTarget.Blur()
+	if a.model.CopyField == copyFieldTarget {
+		a.model.CopyTarget.Focus()
+	}
+}
+
+// copyProfile returns the target profile chosen on the copy form; the active profile keeps
+// the overrides given on the command line
+func (a *App) copyProfile() (config.Profile, error) {
+	name := a.model.CopyProfiles[a.model.CopyProfile]
+	if name == a.profile.Name {
+		return a.profile, nil
+	}
+	return a.settings.Profile(name)
+}
+
+// planCopy checks the target server before asking for confirmation
+func (a *App) planCopy() (tea.Model, tea.Cmd) {
+	target := strings.TrimSpace(a.model.CopyTarget.Value())
+	if target == "" {
+		a.model.CopyError = "Informe o banco de destino"
+		return a, nil
+	}
+	profile, err := a.copyProfile()
+	if err != nil {
+		a.model.CopyError = err.Error()
+		return a, nil
+	}
+	// pg_dump cannot mask, so a copy under a masking profile would carry the raw data
+	if a.model.Masking != "" {
+		a.model.CopyError = fmt.Sprintf("O perfil usa o mascaramento %s, que a cópia não aplica. Desligue-o com [M] na confirmação do backup ou use a exportação mascarada", a.model.Masking)
+		return a, nil
+	}
+
+	a.model.CopyError = ""
+	a.model.CopyChecking = true
+	a.model.CopyTarget.Blur()
+	return a, tea.Batch(a.model.Spinner.Tick, a.planCopyCmd(profile, target))
+}
+
+// planCopyCmd connects to the target profile, checks the target database and picks the clients
+func (a *App) planCopyCmd(profile config.Profile, target string) tea.Cmd {
+	source := a.model.Connection()
+	sourceDump := a.model.PgDump
+	return func() tea.Msg {
+		plan := types.CopyPlanMsg{
+			Profile:    profile.Name,
+			Database:   target,
+			Production: profile.Production && !profile.AllowRestore,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(profile.ConnectTimeout)*time.Second+5*time.Second)
+		defer cancel()
+
+		conn, dbService, session, err := a.connectProfile(ctx, profile)
+		if err != nil {
+			plan.Err = err
+			return plan
+		}
+		defer dbService.Close()
+		if session != nil {
+			defer session.Close()
+		}
+
+		plan.Target = conn
+		plan.Exists, _, plan.Err = dbService.DatabaseStatus(ctx, target)
+
+		pgDump, loader, err := a.backupService.CopyClients(source, sourceDump, conn, profile.PgDump)
+		if err != nil {
+			plan.Warning = err.Error()
+		} else {
+			plan.PgDump, plan.Loader = pgDump.String(), loader.String()
+		}
+		return plan
+	}
+}
+
+// handleCopyPlan shows the copy confirmation once the target has been checked
+func (a *App) handleCopyPlan(msg types.CopyPlanMsg) (tea.Model, tea.Cmd) {
+	if !a.model.CopyChecking {
+		return a, nil
+	}
+	a.model.CopyChecking = false
+	if msg.Err != nil {
+		a.model.CopyError = msg.Err.Error()
+		a.focusCopyField()
+		return a, nil
+	}
+	a.model.CopyPlan = &msg
+	a.model.Screen = types.ScreenCopyConfirm
+	return a, nil
+}
+
+// copyBlocked reports whether the planned copy must not run
+func copyBlocked(plan *types.CopyPlanMsg) bool {
+	return plan.Exists || plan.Production || plan.Warning != ""
+}
+
+// handleCopyConfirmKeys processes keys for the database copy confirmation
+func (a *App) handleCopyConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return a, tea.Quit
+	case "esc":
+		a.model.CopyPlan = nil
+		a.model.Screen = types.ScreenCopySetup
+		a.focusCopyField()
+	case "enter", "y":
+		if copyBlocked(a.model.CopyPlan) {
+			return a, nil
+		}
+		return a.startCopy()
+	}
+	return a, nil
+}
+
+// startCopy creates the target database and streams the source into it in the background
+func (a *App) startCopy() (tea.Model, tea.Cmd) {
+	profile, err := a.copyProfile()
+	if err != nil {
+		a.model.CopyError = err.Error()
+		return a, nil
+	}
+
+	source := a.model.Connection()
+	sourceDB := a.model.Databases[a.model.CopySource]
+	sourceDump := a.model.PgDump
+	target := a.model.CopyPlan.Database
+
+	updates := make(chan tea.Msg, 1)
+	go func() {
+		defer close(updates)
+		result := types.CopyCompleteMsg{Source: sourceDB, Target: target, Profile: profile.Name, StartedAt: time.Now()}
+
+		// Keep only the latest progress so a slow UI never stalls the stream
+		send := func(msg tea.Msg) {
+			select {
+			case <-updates:
+			default:
+			}
+			updates <- msg
+		}
+
+		result.Warnings, result.Err = a.copyDatabase(profile, source, sourceDB, sourceDump, target, func(n int64) {
+			result.Bytes = n
+			send(types.CopyProgressMsg{Bytes: n})
+		})
+		result.FinishedAt = time.Now()
+		send(result)
+	}()
+
+	a.copyUpdates = updates
+	a.model.Copying = true
+	a.model.CopyStarted = time.Now()
+	a.model.CopyProgress = types.CopyProgressMsg{}
+	a.model.CopyResult = nil
+	a.model.Screen = types.ScreenCopyProgress
+	return a, tea.Batch(a.model.Spinner.Tick, waitForUpdate(a.copyUpdates))
+}
+
+// copyDatabase connects to the target profile, creates the target database and copies the
+// source into it, keeping the profile's tunnel open for the whole copy. A failed copy drops the
+// database it created, so the copy can be retried.
+func (a *App) copyDatabase(profile config.Profile, source types.DatabaseConnection, sourceDB string, sourceDump config.PgDumpSettings,
+	target string, progress func(int64)) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(profile.ConnectTimeout)*time.Second+5*time.Second)
+	defer cancel()
+
+	conn, dbService, session, err := a.connectProfile(ctx, profile)
+	if err != nil {
+		return "", err
+	}
+	if session != nil {
+		defer session.Close()
+	}
+	defer dbService.Close()
+	if err := dbService.CreateDatabase(ctx, target); err != nil {
+		return "", err
+	}
+
+	warnings, err := a.backupService.CopyDatabase(source, sourceDB, sourceDump, conn, target, profile.PgDump, progress)
+	if err != nil {
+		a.logger.Error("database copy failed, dropping the partial target", "profile", profile.Name, "database", target, "error", err)
+		dropCtx, dropCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer dropCancel()
+		if dropErr := dbService.DropDatabase(dropCtx, target); dropErr != nil {
+			a.logger.Error("failed to drop partial copy target", "profile", profile.Name, "database", target, "error", dropErr)
+			err = fmt.Errorf("%w\nthe partial database %s could not be dropped: %v", err, target, dropErr)
+		}
+	}
+	return warnings, err
+}
+
+// handleCopyProgress shows a progress update and waits for the next one
+func (a *App) handleCopyProgress(msg types.CopyProgressMsg) (tea.Model, tea.Cmd) {
+	a.model.CopyProgress = msg
+	return a, waitForUpdate(a.copyUpdates)
+}
+
+// handleCopyComplete shows the result of a database copy
+func (a *App) handleCopyComplete(msg types.CopyCompleteMsg) (tea.Model, tea.Cmd) {
+	a.model.Copying = false
+	a.model.CopyResult = &msg
+	a.copyUpdates = nil
+	return a, nil
+}
+
+// handleCopyProgressKeys processes keys for the database copy progress screen
+func (a *App) handleCopyProgressKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return a, tea.Quit
+	case "q":
+		if !a.model.Copying {
+			return a, tea.Quit
+		}
+	case "enter", "esc":
+		if !a.model.Copying {
+			a.model.CopyResult = nil
+			a.model.CopyPlan = nil
+			a.model.Screen = types.ScreenMenu
+			a.model.Cursor = 0
+		}
+	}
+	return a, nil
+}
+
+// waitForUpdate creates a command that waits for the next message of a background run
+func waitForUpdate(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
+	}
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/restore"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// inspectPageSize is the number of entries moved by page up and page down
+const inspectPageSize = 12
+
+// newInspectSearchInput creates the search field of the archive inspector
+func newInspectSearchInput() textinput.Model {
+	search := textinput.New()
+	search.Prompt = ""
+	search.Placeholder = "tipo, esquema ou nome..."
+	search.CharLimit = 80
+	search.Width = 40
+	return search
+}
+
+// openInspect lists the archives, opening the inspector for the chosen one
+func (a *App) openInspect() (tea.Model, tea.Cmd) {
+	model, cmd := a.openRestore()
+	a.model.Inspecting = true
+	return model, cmd
+}
+
+// prepareInspect resets the inspector for the archive just read
+func (a *App) prepareInspect() {
+	a.model.InspectCounts = restore.CountObjects(a.model.RestoreTOC)
+	a.model.InspectSearch.SetValue("")
+	a.model.InspectSearching = false
+	a.model.InspectMatches = restore.FilterEntries(a.model.RestoreTOC, "")
+	a.model.InspectCursor = 0
+	a.model.InspectSize = 0
+	if info, err := os.Stat(a.model.RestoreFile); err == nil {
+		a.model.InspectSize = info.Size()
+	}
+}
+
+// handleInspectKeys processes keys for the archive inspector
+func (a *App) handleInspectKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if a.model.InspectSearching {
+		switch msg.String() {
+		case "ctrl+c":
+			return a, tea.Quit
+		case "esc":
+			// Leave search mode and clear the filter
+			a.model.InspectSearch.SetValue("")
+			fallthrough
+		case "enter":
+			a.model.InspectSearching = false
+			a.model.InspectSearch.Blur()
+		default:
+			var cmd tea.Cmd
+			a.model.InspectSearch, cmd = a.model.InspectSearch.Update(msg)
+			a.model.InspectMatches = restore.FilterEntries(a.model.RestoreTOC, a.model.InspectSearch.Value())
+			a.model.InspectCursor = 0
+			return a, cmd
+		}
+		a.model.InspectMatches = restore.FilterEntries(a.model.RestoreTOC, a.model.InspectSearch.Value())
+		a.model.InspectCursor = 0
+		return a, nil
+	}
+
+	last := max(0, len(a.model.InspectMatches)-1)
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return a, tea.Quit
+	case "esc":
+		if a.model.Inspecting {
+			a.model.Screen = types.ScreenRestoreFile
+		} else {
+			a.model.Screen = types.ScreenRestoreTOC
+		}
+	case "/":
+		a.model.InspectSearching = true
+		return a, a.model.InspectSearch.Focus()
+	case "up", "k":
+		a.model.InspectCursor = max(0, a.model.InspectCursor-1)
+	case "down", "j":
+		a.model.InspectCursor = min(last, a.model.InspectCursor+1)
+	case "pgup":
+		a.model.InspectCursor = max(0, a.model.InspectCursor-inspectPageSize)
+	case "pgdown":
+		a.model.InspectCursor = min(last, a.model.InspectCursor+inspectPageSize)
+	}
+	return a, nil
+}
@@ -0,0 +1,322 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/database"
+	"github.com/Luiz-F3lipe/snapTUI/internal/notify"
+	"github.com/Luiz-F3lipe/snapTUI/internal/tunnel"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// handleProfileSelectKeys processes keys for the profile selection screen of multi-server runs
+func (a *App) handleProfileSelectKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if a.model.LoadingProfiles {
+		if msg.String() == "ctrl+c" {
+			return a, tea.Quit
+		}
+		return a, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return a, tea.Quit
+	case "esc":
+		a.resetMultiBackup()
+		a.model.Screen = types.ScreenMenu
+		a.model.Cursor = 0
+	case "up", "k":
+		if a.model.Cursor > 0 {
+			a.model.Cursor--
+		}
+	case "down", "j":
+		if a.model.Cursor < len(a.model.ProfileNames)-1 {
+			a.model.Cursor++
+		}
+	case " ":
+		a.model.SelectedProfiles[a.model.Cursor] = !a.model.SelectedProfiles[a.model.Cursor]
+	case "enter":
+		var profiles []config.Profile
+		for i, name := range a.model.ProfileNames {
+			if !a.model.SelectedProfiles[i] {
+				continue
+			}
+			profile, err := a.settings.Profile(name)
+			if err != nil {
+				continue
+			}
+			profiles = append(profiles, profile)
+		}
+		if len(profiles) == 0 {
+			return a, nil
+		}
+
+		a.closeMultiSessions()
+		a.model.LoadingProfiles = true
+		a.model.MultiErrors = nil
+		return a, tea.Batch(a.model.Spinner.Tick, a.loadProfilesCmd(profiles))
+	}
+	return a, nil
+}
+
+// loadProfilesCmd connects to every profile concurrently and lists their databases
+func (a *App) loadProfilesCmd(profiles []config.Profile) tea.Cmd {
+	return func() tea.Msg {
+		type result struct {
+			targets []types.BackupTarget
+			session io.Closer
+			err     error
+		}
+
+		results := make([]result, len(profiles))
+		var wg sync.WaitGroup
+		for i, profile := range profiles {
+			wg.Add(1)
+			go func(i int, profile config.Profile) {
+				defer wg.Done()
+				targets, session, err := a.listProfileDatabases(profile)
+				results[i] = result{targets, session, err}
+			}(i, profile)
+		}
+		wg.Wait()
+
+		var msg types.ProfilesLoadedMsg
+		for i, r := range results {
+			if r.err != nil {
+				msg.Errors = append(msg.Errors, fmt.Sprintf("%s: %v", profiles[i].Name, r.err))
+				continue
+			}
+			msg.Targets = append(msg.Targets, r.targets...)
+			if r.session != nil {
+				msg.Sessions = append(msg.Sessions, r.session)
+			}
+		}
+		return msg
+	}
+}
+
+// listProfileDatabases opens the profile's tunnel if needed and lists its databases
+func (a *App) listProfileDatabases(profile config.Profile) ([]types.BackupTarget, io.Closer, error) {
+	// Multi-server runs use pg_dump, which would write the data the profile masks unmasked
+	if profile.Masking != "" {
+		return nil, nil, fmt.Errorf("masking profile %q is not applied by multi-server backups; use the masked export of the profile instead", profile.Masking)
+	}
+	conn := profileConnection(profile)
+	ctx, cancel := context.WithTimeout(context.Background(), conn.ConnectTimeout+5*time.Second)
+	defer cancel()
+
+	conn, dbService, session, err := a.connectProfile(ctx, profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer dbService.Close()
+
+	databases, err := dbService.ListDatabases(ctx)
+	if err != nil {
+		if session != nil {
+			session.Close()
+		}
+		return nil, nil, err
+	}
+
+	targets := make([]types.BackupTarget, 0, len(databases))
+	for _, info := range databases {
+		targets = append(targets, types.BackupTarget{
+			Profile:    profile.Name,
+			Connection: conn,
+			Database:   info.Name,
+			SourceSize: info.Size,
+			PgDump:     profile.PgDump,
+		})
+	}
+	return targets, session, nil
+}
+
+// handleProfilesLoaded shows the combined database list of the selected profiles
+func (a *App) handleProfilesLoaded(msg types.ProfilesLoadedMsg) (tea.Model, tea.Cmd) {
+	a.model.LoadingProfiles = false
+	a.multiSessions = msg.Sessions
+	a.model.MultiTargets = msg.Targets
+	a.model.MultiErrors = msg.Errors
+	a.model.MultiChoices = make(map[int]bool)
+
+	if len(msg.Targets) > 0 {
+		a.model.Screen = types.ScreenMultiBackupList
+		a.model.Cursor = 0
+	}
+	return a, nil
+}
+
+// handleMultiBackupListKeys processes keys for the combined database list
+func (a *App) handleMultiBackupListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return a, tea.Quit
+	case "esc":
+		a.closeMultiSessions()
+		a.model.MultiTargets = nil
+		a.model.Screen = types.ScreenProfileSelect
+		a.model.Cursor = 0
+	case "up", "k":
+		if a.model.Cursor > 0 {
+			a.model.Cursor--
+		}
+	case "down", "j":
+		if a.model.Cursor < len(a.model.MultiTargets)-1 {
+			a.model.Cursor++
+		}
+	case " ":
+		a.model.MultiChoices[a.model.Cursor] = !a.model.MultiChoices[a.model.Cursor]
+	case "a":
+		// Toggle every database of the profile under the cursor
+		profile := a.model.MultiTargets[a.model.Cursor].Profile
+		selectAll := false
+		for i, t := range a.model.MultiTargets {
+			if t.Profile == profile && !a.model.MultiChoices[i] {
+				selectAll = true
+				break
+			}
+		}
+		for i, t := range a.model.MultiTargets {
+			if t.Profile == profile {
+				a.model.MultiChoices[i] = selectAll
+			}
+		}
+	case "enter":
+		var targets []types.BackupTarget
+		for i, t := range a.model.MultiTargets {
+			if a.model.MultiChoices[i] {
+				targets = append(targets, t)
+			}
+		}
+		if len(targets) == 0 {
+			return a, nil
+		}
+
+		a.model.Screen = types.ScreenBackupProgress
+		a.model.MultiRun = true
+		a.model.BackupCompleted = false
+		a.model.IsProcessing = true
+		a.model.TotalBackups = len(targets)
+		return a, tea.Batch(a.model.Spinner.Tick, a.backupService.PerformMultiBackupCmd(targets, a.settings.MaxConcurrentPerHost))
+	}
+	return a, nil
+}
+
+// handleMultiBackupComplete shows the combined summary and notifies each profile
+func (a *App) handleMultiBackupComplete(msg types.MultiBackupCompleteMsg) (tea.Model, tea.Cmd) {
+	a.model.BackupCompleted = true
+	a.model.IsProcessing = false
+	a.model.MultiResults = msg.Results
+	a.model.NotificationErrors = nil
+
+	a.model.BackupSuccess = 0
+	a.model.BackupErrors = nil
+	a.model.BackupFilenames = nil
+	for _, r := range msg.Results {
+		a.model.BackupSuccess += r.Success
+		a.model.BackupErrors = append(a.model.BackupErrors, r.Errors...)
+		a.model.BackupFilenames = append(a.model.BackupFilenames, r.Filenames...)
+	}
+
+	// Each profile is notified through its own targets with its own results
+	var cmds []tea.Cmd
+	for _, r := range msg.Results {
+		profile, err := a.settings.Profile(r.Profile)
+		if err != nil {
+			continue
+		}
+		summary := notify.NewSummary(r.Profile, r.Host, types.BackupCompleteMsg{
+			Success:    r.Success,
+			Errors:     r.Errors,
+			Filenames:  r.Filenames,
+			StartedAt:  msg.StartedAt,
+			FinishedAt: msg.FinishedAt,
+		})
+		cmds = append(cmds, notify.NewService(profile.Notifications, a.logger).SendCmd(summary))
+	}
+	return a, tea.Batch(cmds...)
+}
+
+// resetMultiBackup clears the multi-server selection state
+func (a *App) resetMultiBackup() {
+	a.closeMultiSessions()
+	a.model.MultiRun = false
+	a.model.MultiTargets = nil
+	a.model.MultiResults = nil
+	a.model.MultiErrors = nil
+	a.model.MultiChoices = make(map[int]bool)
+	a.model.SelectedProfiles = make(map[int]bool)
+}
+
+// closeMultiSessions tears down the tunnels opened for a multi-server run
+func (a *App) closeMultiSessions() error {
+	var errs []error
+	for _, session := range a.multiSessions {
+		errs = append(errs, session.Close())
+	}
+	a.multiSessions = nil
+	return errors.Join(errs...)
+}
+
+// connectProfile opens the profile's tunnel if needed and connects to its server. The
+// returned connection carries the tunnel port and server version; the caller closes the
+// database service and the tunnel session, which is nil without SSH.
+func (a *App) connectProfile(ctx context.Context, profile config.Profile) (types.DatabaseConnection, *database.Service, io.Closer, error) {
+	conn := profileConnection(profile)
+
+	var session io.Closer
+	if profile.SSH != nil {
+		tunnelService := tunnel.NewService(a.logger)
+		port, err := tunnelService.Open(ctx, *profile.SSH, conn.Host, conn.Port)
+		if err != nil {
+			return conn, nil, nil, err
+		}
+		conn.TunnelPort = port
+		session = tunnelService
+	}
+
+	dbService := database.NewService(a.logger)
+	err := dbService.Connect(ctx, conn)
+	if err == nil {
+		conn.ServerVersion, err = dbService.ServerVersion(ctx)
+	}
+	if err != nil {
+		dbService.Close()
+		if session != nil {
+			session.Close()
+		}
+		return conn, nil, nil, err
+	}
+	return conn, dbService, session, nil
+}
+
+// profileConnection returns the connection parameters stored in a profile
+func profileConnection(profile config.Profile) types.DatabaseConnection {
+	return types.DatabaseConnection{
+		Host:           profile.Host,
+		Port:           profile.Port,
+		User:           profile.User,
+		Password:       profile.Password,
+		Database:       profile.Database,
+		SSLMode:        profile.SSLMode,
+		ConnectTimeout: time.Duration(profile.ConnectTimeout) * time.Second,
+	}
+}
+
+// profileNames returns the names of the configured profiles
+func profileNames(settings *config.Settings) []string {
+	names := make([]string, 0, len(settings.Profiles))
+	for _, p := range settings.Profiles {
+		names = append(names, p.Name)
+	}
+	return names
+}
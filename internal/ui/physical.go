@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// openPhysical checks the pg_basebackup client and destination before a base backup
+func (a *App) openPhysical() (tea.Model, tea.Cmd) {
+	a.model.PhysicalChecking = true
+	a.model.PhysicalPlan = nil
+	a.model.PhysicalResult = nil
+	a.model.Screen = types.ScreenPhysicalConfirm
+	return a, tea.Batch(a.model.Spinner.Tick, a.planPhysicalCmd())
+}
+
+// planPhysicalCmd creates a command that finds pg_basebackup and the backup directory
+func (a *App) planPhysicalCmd() tea.Cmd {
+	settings := a.model.PgDump
+	serverVersion := a.model.ServerVersion
+	physical := a.profile.Physical
+	return func() tea.Msg {
+		plan := types.PhysicalPlanMsg{WALArchiveDir: physical.WALArchiveDir, Keep: physical.Keep}
+		client, err := a.backupService.PhysicalClient(settings, serverVersion)
+		if err != nil {
+			plan.Err = err
+		} else {
+			plan.Client = client.String()
+		}
+		if plan.Dir, err = a.backupService.BackupDir(); err != nil && plan.Err == nil {
+			plan.Err = err
+		}
+		return plan
+	}
+}
+
+// handlePhysicalPlan shows the base backup confirmation once the client has been found
+func (a *App) handlePhysicalPlan(msg types.PhysicalPlanMsg) (tea.Model, tea.Cmd) {
+	if !a.model.PhysicalChecking {
+		return a, nil
+	}
+	a.model.PhysicalChecking = false
+	a.model.PhysicalPlan = &msg
+	return a, nil
+}
+
+// handlePhysicalConfirmKeys processes keys for the base backup confirmation
+func (a *App) handlePhysicalConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return a, tea.Quit
+	case "esc":
+		a.model.PhysicalChecking = false
+		a.model.Screen = types.ScreenMenu
+	case "enter", "y":
+		if a.model.PhysicalPlan == nil || a.model.PhysicalPlan.Err != nil {
+			return a, nil
+		}
+		a.physicalUpdates = a.backupService.StartPhysicalBackup(a.model.Connection(), a.model.ProfileName, a.model.PgDump, a.profile.Physical)
+		a.model.PhysicalRunning = true
+		a.model.PhysicalStarted = time.Now()
+		a.model.PhysicalProgress = types.PhysicalProgressMsg{}
+		a.model.Screen = types.ScreenPhysicalProgress
+		return a, tea.Batch(a.model.Spinner.Tick, waitForUpdate(a.physicalUpdates))
+	}
+	return a, nil
+}
+
+// handlePhysicalProgress shows a progress update and waits for the next one
+func (a *App) handlePhysicalProgress(msg types.PhysicalProgressMsg) (tea.Model, tea.Cmd) {
+	a.model.PhysicalProgress = msg
+	return a, waitForUpdate(a.physicalUpdates)
+}
+
+// handlePhysicalComplete shows the result of a base backup
+func (a *App) handlePhysicalComplete(msg types.PhysicalCompleteMsg) (tea.Model, tea.Cmd) {
+	a.model.PhysicalRunning = false
+	a.model.PhysicalResult = &msg
+	a.physicalUpdates = nil
+	return a, nil
+}
+
+// handlePhysicalProgressKeys processes keys for the base backup progress screen
+func (a *App) handlePhysicalProgressKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return a, tea.Quit
+	case "q":
+		if !a.model.PhysicalRunning {
+			return a, tea.Quit
+		}
+	case "enter", "esc":
+		if !a.model.PhysicalRunning {
+			a.model.PhysicalResult = nil
+			a.model.PhysicalPlan = nil
+			a.model.Screen = types.ScreenMenu
+			a.model.Cursor = 0
+		}
+	}
+	return a, nil
+}
@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/backup"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// Fields of the point-in-time recovery form
+const (
+	pitrFieldBackup = iota
+	pitrFieldTarget
+	pitrFieldDataDir
+	pitrFieldWALDir
+	pitrFieldCount
+)
+
+// newPITRInputs creates the recovery target, data directory and WAL archive inputs
+func newPITRInputs() (textinput.Model, textinput.Model, textinput.Model) {
+	target := textinput.New()
+	target.Prompt = ""
+	target.Placeholder = "AAAA-MM-DD HH:MM:SS, LSN (0/3000148) ou vazio para todo o WAL"
+	target.CharLimit = 40
+	target.Width = 60
+
+	dataDir := textinput.New()
+	dataDir.Prompt = ""
+	dataDir.Placeholder = "diretório de dados a criar"
+	dataDir.CharLimit = 512
+	dataDir.Width = 60
+
+	walDir := textinput.New()
+	walDir.Prompt = ""
+	walDir.Placeholder = "diretório do arquivo de WAL"
+	walDir.CharLimit = 512
+	walDir.Width = 60
+	return target, dataDir, walDir
+}
+
+// openPITR starts the recovery form with the newest base backup of the catalog
+func (a *App) openPITR() (tea.Model, tea.Cmd) {
+	backups, err := a.backupService.BaseBackups()
+	if err != nil {
+		a.logger.Error("failed to list base backups", "error", err)
+		return a, nil
+	}
+	if len(backups) == 0 {
+		return a, nil
+	}
+
+	a.model.PITRBackups = backups
+	a.model.PITRBackup = 0
+	a.model.PITRTarget.SetValue("")
+	a.model.PITRDataDir.SetValue(backup.PITRDataDir(backups[0].Path))
+	a.model.PITRWALDir.SetValue(a.profile.Physical.WALArchiveDir)
+	a.model.PITRError = ""
+	a.model.PITRResult = nil
+	a.model.PITRField = pitrFieldBackup
+	a.focusPITRField()
+	a.model.Screen = types.ScreenPITRSetup
+	return a, nil
+}
+
+// handlePITRSetupKeys processes keys for the recovery form
+func (a *App) handlePITRSetupKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if a.model.PITRRunning {
+		if msg.String() == "ctrl+c" {
+			return a, tea.Quit
+		}
+		return a, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return a, tea.Quit
+	case "esc":
+		a.model.PITRField = pitrFieldBackup
+		a.focusPITRField()
+		a.model.Screen = types.ScreenMenu
+		return a, nil
+	case "tab", "down":
+		a.model.PITRField = (a.model.PITRField + 1) % pitrFieldCount
+		a.focusPITRField()
+		return a, nil
+	case "shift+tab", "up":
+		a.model.PITRField = (a.model.PITRField + pitrFieldCount - 1) % pitrFieldCount
+		a.focusPITRField()
+		return a, nil
+	case "enter":
+		return a.preparePITR()
+	}
+
+	var cmd tea.Cmd
+	switch a.model.PITRField {
+	case pitrFieldBackup:
+		switch msg.String() {
+		case "left", "h":
+			a.cyclePITRBackup(-1)
+		case "right", "l", " ":
+			a.cyclePITRBackup(1)
+		}
+	case pitrFieldTarget:
+		a.model.PITRTarget, cmd = a.model.PITRTarget.Update(msg)
+	case pitrFieldDataDir:
+		a.model.PITRDataDir, cmd = a.model.PITRDataDir.Update(msg)
+	case pitrFieldWALDir:
+		a.model.PITRWALDir, cmd = a.model.PITRWALDir.Update(msg)
+	}
+	return a, cmd
+}
+
+// cyclePITRBackup selects another base backup, updating the suggested data directory
+func (a *App) cyclePITRBackup(delta int) {
+	n := len(a.model.PITRBackups)
+	previous := a.model.PITRBackups[a.model.PITRBackup].Path
+	a.model.PITRBackup = (a.model.PITRBackup + delta + n) % n
+	if a.model.PITRDataDir.Value() == backup.PITRDataDir(previous) {
+		a.model.PITRDataDir.SetValue(backup.PITRDataDir(a.model.PITRBackups[a.model.PITRBackup].Path))
+	}
+}
+
+// focusPITRField focuses the input of the active recovery form field
+func (a *App) focusPITRField() {
+	a.model.PITRTarget.Blur()
+	a.model.PITRDataDir.Blur()
+	a.model.PITRWALDir.Blur()
+	switch a.model.PITRField {
+	case pitrFieldTarget:
+		a.model.PITRTarget.Focus()
+	case pitrFieldDataDir:
+		a.model.PITRDataDir.Focus()
+	case pitrFieldWALDir:
+		a.model.PITRWALDir.Focus()
+	}
+}
+
+// preparePITR validates the form and unpacks the base backup in the background
+func (a *App) preparePITR() (tea.Model, tea.Cmd) {
+	target, err := backup.ParseRecoveryTarget(a.model.PITRTarget.Value())
+	if err != nil {
+		a.model.PITRError = err.Error()
+		return a, nil
+	}
+	dataDir := strings.TrimSpace(a.model.PITRDataDir.Value())
+	if dataDir == "" {
+		a.model.PITRError = "Informe o diretório de dados"
+		return a, nil
+	}
+	walDir := strings.TrimSpace(a.model.PITRWALDir.Value())
+	if walDir == "" {
+		a.model.PITRError = "Informe o diretório do arquivo de WAL"
+		return a, nil
+	}
+
+	a.model.PITRError = ""
+	a.model.PITRRunning = true
+	return a, tea.Batch(a.model.Spinner.Tick,
+		a.backupService.PreparePITRCmd(a.model.PITRBackups[a.model.PITRBackup], dataDir, walDir, target))
+}
+
+// handlePITRComplete shows the recovery steps, or the error on the form
+func (a *App) handlePITRComplete(msg types.PITRCompleteMsg) (tea.Model, tea.Cmd) {
+	a.model.PITRRunning = false
+	if msg.Err != nil {
+		a.model.PITRError = msg.Err.Error()
+		return a, nil
+	}
+	a.model.PITRResult = &msg
+	a.model.Screen = types.ScreenPITRResult
+	return a, nil
+}
+
+// handlePITRResultKeys processes keys for the recovery steps screen
+func (a *App) handlePITRResultKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return a, tea.Quit
+	case "enter", "esc":
+		a.model.PITRResult = nil
+		a.model.PITRField = pitrFieldBackup
+		a.focusPITRField()
+		a.model.Screen = types.ScreenMenu
+		a.model.Cursor = 0
+	}
+	return a, nil
+}
@@ -0,0 +1,430 @@
+package ui
+
+import (
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/restore"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// Fields of the restore confirmation; only the job count applies to a new database
+const (
+	restoreFieldJobs = iota
+	restoreFieldSafety
+	restoreFieldClean
+	restoreFieldConfirm
+	restoreFieldCount
+)
+
+// newRestoreInputs creates the archive path, target database and confirmation inputs of the restore screens
+func newRestoreInputs() (textinput.Model, textinput.Model, textinput.Model) {
+	path := textinput.New()
+	path.Prompt = ""
+	path.Placeholder = "/caminho/para/backup.backup"
+	path.CharLimit = 1024
+	path.Width = 60
+
+	target := textinput.New()
+	target.Prompt = ""
+	target.Placeholder = "banco de destino"
+	target.CharLimit = 63
+	target.Width = 40
+
+	confirm := textinput.New()
+	confirm.Prompt = ""
+	confirm.Placeholder = "nome do banco"
+	confirm.CharLimit = 63
+	confirm.Width = 40
+	return path, target, confirm
+}
+
+// openRestore lists the archives available for restore
+func (a *App) openRestore() (tea.Model, tea.Cmd) {
+	a.model.RestoreArchives = a.restoreService.Archives()
+	a.model.RestoreCursor = 0
+	a.model.RestoreError = ""
+	a.model.Inspecting = false
+	a.model.RestorePathMode = len(a.model.RestoreArchives) == 0
+	if a.model.RestorePathMode {
+		a.model.RestorePathInput.Focus()
+	}
+	a.model.Screen = types.ScreenRestoreFile
+	return a, nil
+}
+
+// handleRestoreFileKeys processes keys for the archive selection screen
+func (a *App) handleRestoreFileKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if a.model.RestorePathMode {
+		switch msg.String() {
+		case "ctrl+c":
+			return a, tea.Quit
+		case "esc":
+			a.model.RestorePathMode = false
+			a.model.RestorePathInput.Blur()
+			if len(a.model.RestoreArchives) == 0 {
+				a.model.Screen = types.ScreenMenu
+			}
+			return a, nil
+		case "enter":
+			path := strings.TrimSpace(a.model.RestorePathInput.Value())
+			if path == "" {
+				return a, nil
+			}
+			a.model.RestorePathInput.Blur()
+			return a.loadTOC(path)
+		}
+		var cmd tea.Cmd
+		a.model.RestorePathInput, cmd = a.model.RestorePathInput.Update(msg)
+		return a, cmd
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return a, tea.Quit
+	case "esc":
+		a.model.Screen = types.ScreenMenu
+	case "up", "k":
+		if a.model.RestoreCursor > 0 {
+			a.model.RestoreCursor--
+		}
+	case "down", "j":
+		if a.model.RestoreCursor < len(a.model.RestoreArchives)-1 {
+			a.model.RestoreCursor++
+		}
+	case "p":
+		a.model.RestorePathMode = true
+		a.model.RestorePathInput.Focus()
+	case "enter":
+		if len(a.model.RestoreArchives) > 0 {
+			return a.loadTOC(a.model.RestoreArchives[a.model.RestoreCursor].Path)
+		}
+	}
+	return a, nil
+}
+
+// loadTOC reads the table of contents of file in the background
+func (a *App) loadTOC(file string) (tea.Model, tea.Cmd) {
+	a.model.RestoreFile = file
+	a.model.RestoreTOC = nil
+	a.model.RestoreNodes = nil
+	a.model.RestoreChoices = make(map[int]bool)
+	a.model.RestoreCursor = 0
+	a.model.RestoreError = ""
+	a.model.RestoreLoading = true
+	a.model.Screen = types.ScreenRestoreTOC
+	if a.model.Inspecting {
+		a.model.Screen = types.ScreenInspect
+	}
+	return a, tea.Batch(a.model.Spinner.Tick, a.restoreService.LoadTOCCmd(file, a.model.PgDump, a.model.ServerVersion))
+}
+
+// handleRestoreTOCLoaded shows the objects of the archive as a tree
+func (a *App) handleRestoreTOCLoaded(msg types.RestoreTOCLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.File != a.model.RestoreFile {
+		return a, nil
+	}
+	a.model.RestoreLoading = false
+	if msg.Err != nil {
+		a.model.RestoreError = msg.Err.Error()
+		return a, nil
+	}
+	a.model.RestoreHeader = msg.Header
+	a.model.RestoreTOC = msg.Entries
+	a.model.RestoreNodes = restore.BuildTree(msg.Entries)
+	a.prepareInspect()
+	return a, nil
+}
+
+// handleRestoreTOCKeys processes keys for the object selection tree
+func (a *App) handleRestoreTOCKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return a, tea.Quit
+	case "esc":
+		a.model.Screen = types.ScreenRestoreFile
+		a.model.RestoreCursor = 0
+	case "up", "k":
+		if a.model.RestoreCursor > 0 {
+			a.model.RestoreCursor--
+		}
+	case "down", "j":
+		if a.model.RestoreCursor < len(a.model.RestoreNodes)-1 {
+			a.model.RestoreCursor++
+		}
+	case " ":
+		if len(a.model.RestoreNodes) > 0 {
+			a.toggleRestoreNode(a.model.RestoreCursor)
+		}
+	case "i":
+		// Inspect the archive header and entries
+		if !a.model.RestoreLoading && a.model.RestoreError == "" {
+			a.model.Screen = types.ScreenInspect
+		}
+	case "a":
+		// Select everything, or clear the selection when everything is selected
+		selectAll := false
+		for i := range a.model.RestoreNodes {
+			if !a.model.RestoreChoices[i] {
+				selectAll = true
+			}
+		}
+		for i := range a.model.RestoreNodes {
+			a.model.RestoreChoices[i] = selectAll
+		}
+	case "enter":
+		if len(restore.SelectedEntries(a.model.RestoreNodes, a.model.RestoreChoices)) == 0 {
+			return a, nil
+		}
+		a.model.RestoreResult = nil
+		a.model.RestoreError = ""
+		a.model.RestoreStatus = nil
+		a.model.RestoreChecking = false
+		if a.model.RestoreTarget.Value() == "" {
+			a.model.RestoreTarget.SetValue(a.model.RestoreHeader.Database)
+		}
+		a.model.RestoreTarget.Focus()
+		a.model.Screen = types.ScreenRestoreConfirm
+	}
+	return a, nil
+}
+
+// toggleRestoreNode flips a row; a schema row flips itself and every object below it
+func (a *App) toggleRestoreNode(i int) {
+	node := a.model.RestoreNodes[i]
+	if node.Parent >= 0 {
+		a.model.RestoreChoices[i] = !a.model.RestoreChoices[i]
+		return
+	}
+
+	selected := !a.model.RestoreChoices[i]
+	a.model.RestoreChoices[i] = selected
+	for j, child := range a.model.RestoreNodes {
+		if child.Parent == i {
+			a.model.RestoreChoices[j] = selected
+		}
+	}
+}
+
+// restoreBlocked reports whether the profile forbids restores into its server
+func (a *App) restoreBlocked() bool {
+	return a.model.Production && !a.model.AllowRestore
+}
+
+// handleRestoreConfirmKeys processes keys for the restore confirmation screen: first the
+// target database, then, once it has been checked, the safety options and confirmation
+func (a *App) handleRestoreConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if a.model.RestoreChecking {
+		if msg.String() == "ctrl+c" {
+			return a, tea.Quit
+		}
+		return a, nil
+	}
+	if a.model.RestoreStatus != nil {
+		return a.handleRestoreOptionKeys(msg)
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return a, tea.Quit
+	case "esc":
+		a.model.RestoreTarget.Blur()
+		a.model.RestoreError = ""
+		a.model.Screen = types.ScreenRestoreTOC
+		return a, nil
+	case "enter":
+		if a.restoreBlocked() {
+			return a, nil
+		}
+		target := strings.TrimSpace(a.model.RestoreTarget.Value())
+		if target == "" {
+			a.model.RestoreError = "Informe o banco de destino"
+			return a, nil
+		}
+		a.model.RestoreError = ""
+		a.model.RestoreChecking = true
+		return a, tea.Batch(a.model.Spinner.Tick, a.dbService.DatabaseStatusCmd(target))
+	}
+
+	var cmd tea.Cmd
+	a.model.RestoreTarget, cmd = a.model.RestoreTarget.Update(msg)
+	return a, cmd
+}
+
+// handleRestoreTargetStatus moves to the restore options once the target has been checked
+func (a *App) handleRestoreTargetStatus(msg types.DatabaseStatusMsg) (tea.Model, tea.Cmd) {
+	if !a.model.RestoreChecking || msg.Database != strings.TrimSpace(a.model.RestoreTarget.Value()) {
+		return a, nil
+	}
+	a.model.RestoreChecking = false
+	if msg.Err != nil {
+		a.model.RestoreError = msg.Err.Error()
+		return a, nil
+	}
+
+	a.model.RestoreStatus = &msg
+	a.model.RestoreTarget.Blur()
+	a.model.RestoreSafety = msg.Exists
+	a.model.RestoreClean = false
+	a.model.RestoreConfirm.SetValue("")
+	a.model.RestoreField = restoreFieldJobs
+	if msg.Exists {
+		a.model.RestoreField = restoreFieldConfirm
+	}
+	a.focusRestoreField()
+	return a, nil
+}
+
+// restoreFields returns the number of restore options shown for the checked target
+func (a *App) restoreFields() int {
+	if a.model.RestoreStatus != nil && a.model.RestoreStatus.Exists {
+		return restoreFieldCount
+	}
+	return restoreFieldJobs + 1
+}
+
+// parallelRestore reports whether the archive supports pg_restore --jobs
+func (a *App) parallelRestore() bool {
+	switch a.model.RestoreHeader.Format {
+	case "CUSTOM", "DIRECTORY":
+		return true
+	}
+	return false
+}
+
+// adjustRestoreJobs changes the number of parallel jobs by delta, between 1 and the CPU count
+func (a *App) adjustRestoreJobs(delta int) {
+	if !a.parallelRestore() {
+		a.model.RestoreJobs = 1
+		return
+	}
+	a.model.RestoreJobs = min(max(1, a.model.RestoreJobs+delta), runtime.NumCPU())
+}
+
+// handleRestoreOptionKeys processes keys for the restore options of a checked target
+func (a *App) handleRestoreOptionKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	status := a.model.RestoreStatus
+	switch msg.String() {
+	case "ctrl+c":
+		return a, tea.Quit
+	case "esc":
+		// Back to the target name
+		a.model.RestoreStatus = nil
+		a.model.RestoreError = ""
+		a.model.RestoreConfirm.Blur()
+		a.model.RestoreTarget.Focus()
+		return a, nil
+	case "tab", "down":
+		a.model.RestoreField = (a.model.RestoreField + 1) % a.restoreFields()
+		a.focusRestoreField()
+		return a, nil
+	case "shift+tab", "up":
+		a.model.RestoreField = (a.model.RestoreField + a.restoreFields() - 1) % a.restoreFields()
+		a.focusRestoreField()
+		return a, nil
+	case "enter":
+		return a.startRestore()
+	}
+
+	var cmd tea.Cmd
+	switch a.model.RestoreField {
+	case restoreFieldJobs:
+		switch msg.String() {
+		case "left", "h", "-":
+			a.adjustRestoreJobs(-1)
+		case "right", "l", "+":
+			a.adjustRestoreJobs(1)
+		}
+	case restoreFieldSafety:
+		if msg.String() == " " {
+			a.model.RestoreSafety = !a.model.RestoreSafety
+		}
+	case restoreFieldClean:
+		if msg.String() == " " {
+			a.model.RestoreClean = !a.model.RestoreClean
+		}
+	case restoreFieldConfirm:
+		if status.Exists {
+			a.model.RestoreConfirm, cmd = a.model.RestoreConfirm.Update(msg)
+		}
+	}
+	return a, cmd
+}
+
+// startRestore runs the restore once an existing target has been confirmed by name
+func (a *App) startRestore() (tea.Model, tea.Cmd) {
+	if a.restoreBlocked() {
+		return a, nil
+	}
+	status := a.model.RestoreStatus
+	target := status.Database
+	if status.Exists && a.model.RestoreConfirm.Value() != target {
+		a.model.RestoreError = "Digite o nome do banco de destino para confirmar"
+		a.model.RestoreField = restoreFieldConfirm
+		a.focusRestoreField()
+		return a, nil
+	}
+
+	opts := restore.Options{CreateTarget: !status.Exists, Jobs: a.model.RestoreJobs}
+	if !a.parallelRestore() {
+		opts.Jobs = 1
+	}
+	if status.Exists {
+		opts.SafetyBackup = a.model.RestoreSafety
+		opts.Clean = a.model.RestoreClean
+	}
+	selected := restore.SelectedEntries(a.model.RestoreNodes, a.model.RestoreChoices)
+
+	a.model.RestoreError = ""
+	a.model.RestoreConfirm.Blur()
+	a.model.Restoring = true
+	a.model.RestoreStarted = time.Now()
+	a.model.RestoreProgress = types.RestoreProgressMsg{Total: len(selected)}
+	a.model.Screen = types.ScreenRestoreProgress
+	a.restoreUpdates = a.restoreService.StartRestore(a.model.Connection(), a.model.RestoreFile, target,
+		a.model.RestoreTOC, selected, a.model.PgDump, opts)
+	return a, tea.Batch(a.model.Spinner.Tick, waitForUpdate(a.restoreUpdates))
+}
+
+// handleRestoreProgress shows a progress update and waits for the next one
+func (a *App) handleRestoreProgress(msg types.RestoreProgressMsg) (tea.Model, tea.Cmd) {
+	a.model.RestoreProgress = msg
+	return a, waitForUpdate(a.restoreUpdates)
+}
+
+// focusRestoreField focuses the confirmation input when it is the active field
+func (a *App) focusRestoreField() {
+	a.model.RestoreConfirm.Blur()
+	if a.model.RestoreField == restoreFieldConfirm && a.model.RestoreStatus != nil && a.model.RestoreStatus.Exists {
+		a.model.RestoreConfirm.Focus()
+	}
+}
+
+// handleRestoreComplete shows the result of a restore run
+func (a *App) handleRestoreComplete(msg types.RestoreCompleteMsg) (tea.Model, tea.Cmd) {
+	a.model.Restoring = false
+	a.model.RestoreResult = &msg
+	a.restoreUpdates = nil
+	return a, nil
+}
+
+// handleRestoreProgressKeys processes keys for the restore progress screen
+func (a *App) handleRestoreProgressKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		if !a.model.Restoring || msg.String() == "ctrl+c" {
+			return a, tea.Quit
+		}
+	case "enter", "esc":
+		if !a.model.Restoring {
+			a.model.RestoreResult = nil
+			a.model.RestoreStatus = nil
+			a.model.Screen = types.ScreenRestoreTOC
+		}
+	}
+	return a, nil
+}
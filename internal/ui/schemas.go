@@ -0,0 +1,102 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// openSchemas drills from the database under the cursor into its schemas
+func (a *App) openSchemas() (tea.Model, tea.Cmd) {
+	page := a.getCurrentPageDatabases()
+	if len(page) == 0 || page[a.model.Cursor] == a.model.Databases[0] { // not "All Databases"
+		return a, nil
+	}
+
+	a.model.SchemaDatabase = page[a.model.Cursor]
+	a.model.Schemas = nil
+	a.model.SchemaCursor = 0
+	a.model.SchemaChoices = make(map[int]bool)
+	a.model.SchemaError = ""
+	a.model.LoadingSchemas = true
+	a.model.Screen = types.ScreenSchemaList
+	return a, tea.Batch(a.model.Spinner.Tick, a.dbService.ListSchemasCmd(a.model.SchemaDatabase))
+}
+
+// handleSchemasLoaded shows the schemas of the selected database
+func (a *App) handleSchemasLoaded(msg types.SchemasLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.Database != a.model.SchemaDatabase {
+		return a, nil
+	}
+	a.model.LoadingSchemas = false
+	if msg.Err != nil {
+		a.model.SchemaError = msg.Err.Error()
+		return a, nil
+	}
+	a.model.Schemas = msg.Schemas
+	return a, nil
+}
+
+// handleSchemaListKeys processes keys for the schema selection screen
+func (a *App) handleSchemaListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return a, tea.Quit
+	case "esc":
+		a.model.Screen = types.ScreenBackupList
+	case "up", "k":
+		if a.model.SchemaCursor > 0 {
+			a.model.SchemaCursor--
+		}
+	case "down", "j":
+		if a.model.SchemaCursor < len(a.model.Schemas)-1 {
+			a.model.SchemaCursor++
+		}
+	case " ":
+		if len(a.model.Schemas) > 0 {
+			a.model.SchemaChoices[a.model.SchemaCursor] = !a.model.SchemaChoices[a.model.SchemaCursor]
+		}
+	case "a":
+		// Select every schema, or clear the selection when all are selected
+		selectAll := len(a.selectedSchemas()) < len(a.model.Schemas)
+		for i := range a.model.Schemas {
+			a.model.SchemaChoices[i] = selectAll
+		}
+	case "enter":
+		schemas := a.selectedSchemas()
+		if len(schemas) == 0 {
+			return a, nil
+		}
+
+		// Same pre-flight check and confirmation as a database run
+		a.model.BackupSchemas = schemas
+		a.model.Preflight = a.backupService.SchemaPreflight(a.model, a.model.SchemaDatabase, schemas)
+		a.model.Screen = types.ScreenBackupConfirm
+	}
+	return a, nil
+}
+
+// startSchemaBackup runs one pg_dump per confirmed schema, reported on the regular backup progress screen
+func (a *App) startSchemaBackup() (tea.Model, tea.Cmd) {
+	if a.model.Preflight.Blocked {
+		return a, nil
+	}
+	schemas := a.model.BackupSchemas
+	a.model.BackupSchemas = nil
+	a.model.Screen = types.ScreenBackupProgress
+	a.model.BackupCompleted = false
+	a.model.IsProcessing = true
+	a.model.TotalBackups = len(schemas)
+	return a, tea.Batch(a.model.Spinner.Tick, a.backupService.PerformSchemaBackupCmd(a.model, a.model.SchemaDatabase, schemas))
+}
+
+// selectedSchemas returns the schemas checked on the schema list, in list order
+func (a *App) selectedSchemas() []types.SchemaInfo {
+	var schemas []types.SchemaInfo
+	for i, sc := range a.model.Schemas {
+		if a.model.SchemaChoices[i] {
+			schemas = append(schemas, sc)
+		}
+	}
+	return schemas
+}
@@ -0,0 +1,243 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/export"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+)
+
+// Fields of the per-table export form
+const (
+	exportFieldFormat = iota
+	exportFieldWhere
+	exportFieldLimit
+	exportFieldCount
+)
+
+// newExportInputs creates the WHERE filter and row limit inputs of the per-table export form
+func newExportInputs() (textinput.Model, textinput.Model) {
+	where := textinput.New()
+	where.Prompt = ""
+	where.Placeholder = "created_at >= '2024-01-01' (opcional)"
+	where.CharLimit = 1024
+	where.Width = 60
+
+	limit := textinput.New()
+	limit.Prompt = ""
+	limit.Placeholder = "sem limite"
+	limit.CharLimit = 12
+	limit.Width = 12
+	return where, limit
+}
+
+// openTables drills from the database under the cursor into its tables
+func (a *App) openTables() (tea.Model, tea.Cmd) {
+	page := a.getCurrentPageDatabases()
+	if len(page) == 0 || page[a.model.Cursor] == a.model.Databases[0] { // not "All Databases"
+		return a, nil
+	}
+
+	a.model.TableDatabase = page[a.model.Cursor]
+	a.model.Tables = nil
+	a.model.TableCursor = 0
+	a.model.TableChoices = make(map[int]bool)
+	a.model.TableError = ""
+	a.model.LoadingTables = true
+	a.model.Screen = types.ScreenTableList
+	return a, tea.Batch(a.model.Spinner.Tick, a.dbService.ListTablesCmd(a.model.TableDatabase))
+}
+
+// handleTablesLoaded shows the tables of the selected database
+func (a *App) handleTablesLoaded(msg types.TablesLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.Database != a.model.TableDatabase {
+		return a, nil
+	}
+	a.model.LoadingTables = false
+	if msg.Err != nil {
+		a.model.TableError = msg.Err.Error()
+		return a, nil
+	}
+	a.model.Tables = msg.Tables
+	return a, nil
+}
+
+// handleTableListKeys processes keys for the table selection screen
+func (a *App) handleTableListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return a, tea.Quit
+	case "esc":
+		a.model.Screen = types.ScreenBackupList
+	case "up", "k":
+		if a.model.TableCursor > 0 {
+			a.model.TableCursor--
+		}
+	case "down", "j":
+		if a.model.TableCursor < len(a.model.Tables)-1 {
+			a.model.TableCursor++
+		}
+	case " ":
+		if len(a.model.Tables) > 0 {
+			a.model.TableChoices[a.model.TableCursor] = !a.model.TableChoices[a.model.TableCursor]
+		}
+	case "a":
+		// Select every table, or clear the selection when all are selected
+		selectAll := len(a.selectedTables()) < len(a.model.Tables)
+		for i := range a.model.Tables {
+			a.model.TableChoices[i] = selectAll
+		}
+	case "enter":
+		if len(a.selectedTables()) == 0 {
+			return a, nil
+		}
+		a.model.ExportResult = nil
+		a.model.ExportError = ""
+		a.model.ExportField = exportFieldFormat
+		a.focusExportField()
+		a.model.Screen = types.ScreenTableExport
+	}
+	return a, nil
+}
+
+// handleTableExportKeys processes keys for the per-table export form
+func (a *App) handleTableExportKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if a.model.Exporting {
+		if msg.String() == "ctrl+c" {
+			return a, tea.Quit
+		}
+		return a, nil
+	}
+	if a.model.ExportResult != nil {
+		// Any key returns to the table list after an export
+		a.model.ExportResult = nil
+		a.model.Screen = types.ScreenTableList
+		return a, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return a, tea.Quit
+	case "esc":
+		a.model.ExportWhere.Blur()
+		a.model.ExportLimit.Blur()
+		a.model.Screen = types.ScreenTableList
+		return a, nil
+	case "tab", "down":
+		a.model.ExportField = (a.model.ExportField + 1) % exportFieldCount
+		a.focusExportField()
+		return a, nil
+	case "shift+tab", "up":
+		a.model.ExportField = (a.model.ExportField + exportFieldCount - 1) % exportFieldCount
+		a.focusExportField()
+		return a, nil
+	case "enter":
+		return a.startTableExport()
+	}
+
+	var cmd tea.Cmd
+	switch a.model.ExportField {
+	case exportFieldFormat:
+		switch msg.String() {
+		case "left", "h":
+			a.cycleExportFormat(-1)
+		case "right", "l", " ":
+			a.cycleExportFormat(1)
+		}
+	case exportFieldWhere:
+		a.model.ExportWhere, cmd = a.model.ExportWhere.Update(msg)
+	case exportFieldLimit:
+		a.model.ExportLimit, cmd = a.model.ExportLimit.Update(msg)
+	}
+	return a, cmd
+}
+
+// startTableExport validates the form and exports the selected tables
+func (a *App) startTableExport() (tea.Model, tea.Cmd) {
+	opts := export.TableOptions{
+		Format: a.model.ExportFormat,
+		Where:  strings.TrimSpace(a.model.ExportWhere.Value()),
+	}
+	if value := a.model.ExportLimit.Value(); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			a.model.ExportError = "Limite de linhas deve ser um número inteiro positivo"
+			return a, nil
+		}
+		opts.Limit = limit
+	}
+	if err := opts.Validate(); err != nil {
+		a.model.ExportError = err.Error()
+		return a, nil
+	}
+
+	dir, err := a.backupService.BackupDir()
+	if err != nil {
+		a.model.ExportError = err.Error()
+		return a, nil
+	}
+
+	// The profile's masking applies to table exports as it does to database exports
+	var masking *config.MaskingProfile
+	if a.model.Masking != "" {
+		profile, err := a.settings.MaskingProfile(a.model.Masking)
+		if err != nil {
+			a.model.ExportError = err.Error()
+			return a, nil
+		}
+		masking = &profile
+	}
+
+	a.model.ExportError = ""
+	a.model.Exporting = true
+	a.model.ExportWhere.Blur()
+	a.model.ExportLimit.Blur()
+	return a, tea.Batch(a.model.Spinner.Tick, a.exportService.ExportTablesCmd(a.model.TableDatabase, a.selectedTables(), opts, dir, masking))
+}
+
+// handleTableExportComplete shows the files written by a per-table export
+func (a *App) handleTableExportComplete(msg types.TableExportCompleteMsg) (tea.Model, tea.Cmd) {
+	a.model.Exporting = false
+	a.model.ExportResult = &msg
+	return a, nil
+}
+
+// selectedTables returns the tables checked on the table list, in list order
+func (a *App) selectedTables() []types.TableInfo {
+	var tables []types.TableInfo
+	for i, t := range a.model.Tables {
+		if a.model.TableChoices[i] {
+			tables = append(tables, t)
+		}
+	}
+	return tables
+}
+
+// cycleExportFormat moves the selected export format by delta
+func (a *App) cycleExportFormat(delta int) {
+	current := 0
+	for i, format := range export.Formats {
+		if format == a.model.ExportFormat {
+			current = i
+		}
+	}
+	n := len(export.Formats)
+	a.model.ExportFormat = export.Formats[(current+delta+n)%n]
+}
+
+// focusExportField focuses the text input of the active export form field
+func (a *App) focusExportField() {
+	a.model.ExportWhere.Blur()
+	a.model.ExportLimit.Blur()
+	switch a.model.ExportField {
+	case exportFieldWhere:
+		a.model.ExportWhere.Focus()
+	case exportFieldLimit:
+		a.model.ExportLimit.Focus()
+	}
+}
@@ -0,0 +1,89 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// compareHeight is the number of diff lines shown at once
+const compareHeight = 15
+
+// RenderCompareSelect renders the live databases and backups to choose the two sides of a comparison
+func RenderCompareSelect(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+	if m.CompareLeft < 0 {
+		s += config.TextStyle.Render("Comparar esquemas - selecione A (origem)") + "\n\n"
+	} else {
+		s += config.TextStyle.Render("Comparar esquemas - selecione B (destino)") + "\n"
+		s += config.TextStyle.Render("A: "+m.CompareSources[m.CompareLeft].Label) + "\n\n"
+	}
+
+	start := max(0, m.CompareCursor-restoreListHeight/2)
+	end := min(len(m.CompareSources), start+restoreListHeight)
+	start = max(0, end-restoreListHeight)
+
+	for i := start; i < end; i++ {
+		mark := "   "
+		if i == m.CompareLeft {
+			mark = "[A]"
+		}
+		label := mark + " " + truncate(m.CompareSources[i].Label, 80)
+		if i == m.CompareCursor {
+			s += config.SelectedStyle.Render("-➤ " + label)
+		} else {
+			s += config.MenuStyle.Render("  " + label)
+		}
+		s += "\n"
+	}
+
+	if m.CompareLoading {
+		s += "\n" + m.Spinner.View() + " Lendo esquemas...\n"
+		return s
+	}
+	if m.CompareError != "" {
+		s += "\n" + config.ErrorStyle.Render("❌ "+m.CompareError) + "\n"
+	}
+	s += "\n" + config.TextStyle.Render("[↑ ↓ ou J K] Navegar   [Enter] Selecionar   [Esc] Voltar") + "\n"
+	return s
+}
+
+// RenderCompareResult renders the scrollable differences between the two schemas
+func RenderCompareResult(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+	r := m.CompareResult
+	s += config.TextStyle.Render("A: "+r.Left) + "\n"
+	s += config.TextStyle.Render("B: "+r.Right) + "\n"
+	s += config.TextStyle.Render(fmt.Sprintf("%d alteração(ões) de A para B", len(r.Changes))) + "\n\n"
+
+	end := min(len(m.CompareLines), m.CompareOffset+compareHeight)
+	for _, line := range m.CompareLines[m.CompareOffset:end] {
+		switch strings.TrimLeft(line, " ")[:1] {
+		case "+":
+			s += config.SuccessStyle.Render(line)
+		case "-":
+			s += config.ErrorStyle.Render(line)
+		default:
+			s += config.MenuStyle.Render(line)
+		}
+		s += "\n"
+	}
+	if len(m.CompareLines) > compareHeight {
+		s += config.TextStyle.Render(fmt.Sprintf("linhas %d-%d de %d", m.CompareOffset+1, end, len(m.CompareLines))) + "\n"
+	}
+
+	if m.CompareStatus != "" {
+		s += "\n" + config.TextStyle.Render(m.CompareStatus) + "\n"
+	}
+	s += "\n" + config.TextStyle.Render("[↑ ↓ PgUp PgDn] Rolar   [E] Exportar texto   [Esc] Voltar   [Q] Sair") + "\n"
+	return s
+}
@@ -0,0 +1,122 @@
+package views
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RenderCopySetup renders the source, target profile and target name of a database copy
+func RenderCopySetup(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+	s += config.TextStyle.Render("Copiar banco") + "\n\n"
+
+	fields := []struct {
+		label string
+		value string
+	}{
+		{"Banco de origem", fmt.Sprintf("◀ %s ▶", m.Databases[m.CopySource])},
+		{"Perfil de destino", fmt.Sprintf("◀ %s ▶", m.CopyProfiles[m.CopyProfile])},
+		{"Banco de destino", m.CopyTarget.View()},
+	}
+	for i, f := range fields {
+		if i == m.CopyField {
+			s += config.SelectedStyle.Render(fmt.Sprintf("-➤ %-18s", f.label+":")) + " " + f.value + "\n"
+		} else {
+			s += config.MenuStyle.Render(fmt.Sprintf("  %-18s", f.label+":")) + " " + f.value + "\n"
+		}
+	}
+
+	if m.CopyChecking {
+		s += "\n" + m.Spinner.View() + " Verificando servidor de destino...\n"
+	}
+	if m.CopyError != "" {
+		s += "\n" + config.ErrorStyle.Render("⚠️  "+m.CopyError) + "\n"
+	}
+
+	s += "\n" + config.TextStyle.Render("[Tab ↑ ↓] Campos   [← →] Alterar   [Enter] Continuar   [Esc] Voltar") + "\n"
+	return s
+}
+
+// RenderCopyConfirm renders the checks shown before a database copy
+func RenderCopyConfirm(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+	p := m.CopyPlan
+	source := m.Connection()
+	sourceDB := m.Databases[m.CopySource]
+
+	s += config.TextStyle.Render("Confirmação da Cópia") + "\n\n"
+	s += config.TextStyle.Render(fmt.Sprintf("Origem:                %s:%s/%s", source.Host, source.Port, sourceDB)) + "\n"
+	s += config.TextStyle.Render(fmt.Sprintf("Tamanho de origem:     %s", FormatBytes(m.DatabaseInfo[sourceDB].Size))) + "\n"
+	s += config.TextStyle.Render(fmt.Sprintf("Destino:               %s:%s/%s (perfil %s)", p.Target.Host, p.Target.Port, p.Database, p.Profile)) + "\n"
+	if p.PgDump != "" {
+		s += config.TextStyle.Render(fmt.Sprintf("pg_dump:               %s", p.PgDump)) + "\n"
+		s += config.TextStyle.Render(fmt.Sprintf("Carga:                 %s", p.Loader)) + "\n"
+	}
+
+	if p.Warning != "" {
+		s += "\n" + config.ErrorStyle.Render("⚠️  "+p.Warning) + "\n"
+	}
+
+	s += "\n"
+	switch {
+	case p.Production:
+		s += config.ErrorStyle.Render(fmt.Sprintf("Cópia bloqueada: o perfil %s está marcado como produção.", p.Profile)) + "\n"
+		s += config.TextStyle.Render("Defina \"allow_restore\": true no perfil de destino para permitir cópias para ele.") + "\n\n"
+		s += config.TextStyle.Render("[Esc] Voltar   [Q] Sair") + "\n"
+	case p.Exists:
+		s += config.ErrorStyle.Render(fmt.Sprintf("Cópia bloqueada: o banco %s já existe no destino. Escolha outro nome.", p.Database)) + "\n\n"
+		s += config.TextStyle.Render("[Esc] Voltar   [Q] Sair") + "\n"
+	case p.Warning != "":
+		s += config.ErrorStyle.Render("Cópia bloqueada. Instale os clientes compatíveis com os servidores de origem e destino.") + "\n\n"
+		s += config.TextStyle.Render("[Esc] Voltar   [Q] Sair") + "\n"
+	default:
+		s += config.TextStyle.Render(fmt.Sprintf("O banco %s será criado no destino e receberá os dados diretamente do pg_dump, sem arquivo intermediário.", p.Database)) + "\n\n"
+		s += config.TextStyle.Render("[Enter/Y] Iniciar Cópia   [Esc] Voltar   [Q] Sair") + "\n"
+	}
+	return s
+}
+
+// RenderCopyProgress renders the database copy progress and result
+func RenderCopyProgress(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+	sourceDB := m.Databases[m.CopySource]
+
+	r := m.CopyResult
+	if m.Copying || r == nil {
+		s += config.TextStyle.Render(fmt.Sprintf("Copiando %s para %s...", sourceDB, m.CopyPlan.Database)) + "\n\n"
+		s += m.Spinner.View() + fmt.Sprintf(" %s transferidos", FormatBytes(m.CopyProgress.Bytes)) + "\n\n"
+		s += config.TextStyle.Render(fmt.Sprintf("Tempo decorrido: %s", time.Since(m.CopyStarted).Round(time.Second))) + "\n"
+		return s
+	}
+
+	if r.Err != nil {
+		s += config.ErrorStyle.Render("✗ Falha na cópia") + "\n\n"
+		s += config.ErrorStyle.Render(r.Err.Error()) + "\n"
+		s += "\n" + config.TextStyle.Render(fmt.Sprintf("O banco %s pode ter sido criado no destino com dados incompletos.", r.Target)) + "\n"
+	} else {
+		s += config.SuccessStyle.Render("✓ Cópia Concluída!") + "\n\n"
+		s += config.TextStyle.Render(fmt.Sprintf("%s → %s (perfil %s)", r.Source, r.Target, r.Profile)) + "\n"
+		s += config.TextStyle.Render(fmt.Sprintf("Transferido: %s", FormatBytes(r.Bytes))) + "\n"
+		s += config.TextStyle.Render(fmt.Sprintf("Duração: %s", r.FinishedAt.Sub(r.StartedAt).Round(time.Second))) + "\n"
+		if r.Warnings != "" {
+			s += "\n" + config.TextStyle.Render("Mensagens:") + "\n"
+			s += config.TextStyle.Render(r.Warnings) + "\n"
+		}
+	}
+
+	s += "\n" + config.TextStyle.Render("[Enter/Esc] Voltar ao Menu   [Q] Sair") + "\n"
+	return s
+}
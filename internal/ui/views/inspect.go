@@ -0,0 +1,122 @@
+package views
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// inspectListHeight is the number of archive entries shown at once
+const inspectListHeight = 12
+
+// RenderInspect renders the header, object counts and searchable entries of an archive
+func RenderInspect(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+	s += config.TextStyle.Render(fmt.Sprintf("Inspecionar %s", filepath.Base(m.RestoreFile))) + "\n"
+
+	if m.RestoreLoading {
+		s += "\n" + m.Spinner.View() + " Lendo índice do arquivo...\n"
+		return s
+	}
+	if m.RestoreError != "" {
+		s += "\n" + config.ErrorStyle.Render("❌ "+m.RestoreError) + "\n\n"
+		s += config.TextStyle.Render("[Esc] Voltar") + "\n"
+		return s
+	}
+
+	h := m.RestoreHeader
+	format := orDash(h.Format)
+	if h.ArchiveVersion != "" {
+		format += " (versão do arquivo " + h.ArchiveVersion + ")"
+	}
+	s += "\n"
+	for _, row := range [][2]string{
+		{"Arquivo", fmt.Sprintf("%s (%s)", m.RestoreFile, FormatBytes(m.InspectSize))},
+		{"Formato", format},
+		{"Banco de origem", orDash(h.Database)},
+		{"Criado em", orDash(h.CreatedAt)},
+		{"Servidor", orDash(h.DumpedFrom)},
+		{"pg_dump", orDash(h.DumpedBy)},
+		{"Compressão", compressionLabel(h.Compression)},
+		{"Objetos", strconv.Itoa(len(m.RestoreTOC))},
+	} {
+		s += config.TextStyle.Render(fmt.Sprintf("%-17s %s", row[0]+":", row[1])) + "\n"
+	}
+
+	// Object counts, three per line
+	s += "\n" + config.TextStyle.Render("Objetos por tipo:") + "\n"
+	for i := 0; i < len(m.InspectCounts); i += 3 {
+		line := ""
+		for _, c := range m.InspectCounts[i:min(i+3, len(m.InspectCounts))] {
+			line += fmt.Sprintf("%-22s %5d   ", truncate(c.Desc, 22), c.Count)
+		}
+		s += config.MenuStyle.Render(line) + "\n"
+	}
+
+	s += "\n"
+	if m.InspectSearching {
+		s += config.SearchInputActiveStyle.Render("🔍 "+m.InspectSearch.View()) + "\n"
+	} else if m.InspectSearch.Value() != "" {
+		s += config.SearchInputStyle.Render("🔍 "+m.InspectSearch.Value()) + "\n"
+	}
+	s += config.TextStyle.Render(fmt.Sprintf("   %6s  %-22s %-16s %-36s %s", "ID", "Tipo", "Esquema", "Nome", "Dono")) + "\n"
+
+	start := max(0, m.InspectCursor-inspectListHeight/2)
+	end := min(len(m.InspectMatches), start+inspectListHeight)
+	start = max(0, end-inspectListHeight)
+	for i := start; i < end; i++ {
+		e := m.RestoreTOC[m.InspectMatches[i]]
+		label := fmt.Sprintf("%6d  %-22s %-16s %-36s %s", e.ID, truncate(e.Desc, 22), truncate(orDash(e.Namespace), 16),
+			truncate(e.Tag, 36), e.Owner)
+		if i == m.InspectCursor {
+			s += config.SelectedStyle.Render("-➤ " + label)
+		} else {
+			s += config.MenuStyle.Render("  " + label)
+		}
+		s += "\n"
+	}
+	if len(m.InspectMatches) == 0 {
+		s += config.TextStyle.Render("Nenhum objeto encontrado") + "\n"
+	} else {
+		s += config.TextStyle.Render(fmt.Sprintf("%d de %d objeto(s)", len(m.InspectMatches), len(m.RestoreTOC))) + "\n"
+	}
+
+	if m.InspectSearching {
+		s += "\n" + config.TextStyle.Render("[Enter] Aplicar   [Esc] Limpar busca") + "\n"
+	} else {
+		s += "\n" + config.TextStyle.Render("[↑ ↓ PgUp PgDn] Navegar   [/] Buscar   [Esc] Voltar   [Q] Sair") + "\n"
+	}
+	return s
+}
+
+// compressionLabel describes the compression printed by pg_restore: an algorithm name since
+// PostgreSQL 16, or a zlib level before that
+func compressionLabel(value string) string {
+	switch value {
+	case "":
+		return "-"
+	case "-1":
+		return "gzip (nível padrão)"
+	case "0", "none":
+		return "nenhuma"
+	}
+	if level, err := strconv.Atoi(value); err == nil {
+		return fmt.Sprintf("gzip (nível %d)", level)
+	}
+	return value
+}
+
+// orDash returns value, or "-" when it is empty
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
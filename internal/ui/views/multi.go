@@ -0,0 +1,140 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// multiListHeight is the number of rows shown at once in the combined database list
+const multiListHeight = 15
+
+// RenderProfileSelect renders the profile selection screen of multi-server runs
+func RenderProfileSelect(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+	s += config.TextStyle.Render("Backup Multi-servidor: selecione os perfis") + "\n\n"
+
+	for i, name := range m.ProfileNames {
+		prefix := "[ ] "
+		if m.SelectedProfiles[i] {
+			prefix = "[x] "
+		}
+
+		if i == m.Cursor {
+			if m.SelectedProfiles[i] {
+				s += config.CheckedCursorStyle.Render("-➤ " + prefix + name)
+			} else {
+				s += config.SelectedStyle.Render("-➤ " + prefix + name)
+			}
+		} else {
+			if m.SelectedProfiles[i] {
+				s += config.CheckedStyle.Render("  " + prefix + name)
+			} else {
+				s += config.MenuStyle.Render("  " + prefix + name)
+			}
+		}
+		s += "\n"
+	}
+
+	if len(m.MultiErrors) > 0 {
+		s += "\n" + config.ErrorStyle.Render("⚠️  Falha ao conectar:") + "\n"
+		for _, err := range m.MultiErrors {
+			s += config.ErrorStyle.Render(fmt.Sprintf("  • %s", err)) + "\n"
+		}
+	}
+
+	s += "\n"
+	if m.LoadingProfiles {
+		s += m.Spinner.View() + " Conectando aos perfis selecionados...\n"
+		return s
+	}
+	s += config.TextStyle.Render("[↑ ↓ ou J K] Navegar   [Espaço] Selecionar   [Enter] Listar bancos   [Esc] Voltar") + "\n"
+
+	return s
+}
+
+// RenderMultiBackupList renders the databases of every selected profile, grouped by profile
+func RenderMultiBackupList(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+
+	// Scroll window around the cursor
+	start := max(0, m.Cursor-multiListHeight/2)
+	end := min(len(m.MultiTargets), start+multiListHeight)
+	start = max(0, end-multiListHeight)
+
+	selected := 0
+	for _, ok := range m.MultiChoices {
+		if ok {
+			selected++
+		}
+	}
+
+	for i := start; i < end; i++ {
+		t := m.MultiTargets[i]
+
+		// Group header when the profile changes
+		if i == start || m.MultiTargets[i-1].Profile != t.Profile {
+			s += config.TitleStyle.Render(fmt.Sprintf("  %s (%s:%s)", t.Profile, t.Connection.Host, t.Connection.Port)) + "\n"
+		}
+
+		prefix := "[ ] "
+		if m.MultiChoices[i] {
+			prefix = "[x] "
+		}
+		label := fmt.Sprintf("%-28s %10s", truncate(t.Database, 28), FormatBytes(t.SourceSize))
+
+		if i == m.Cursor {
+			if m.MultiChoices[i] {
+				s += config.CheckedCursorStyle.Render("-➤ " + prefix + label)
+			} else {
+				s += config.SelectedStyle.Render("-➤ " + prefix + label)
+			}
+		} else {
+			if m.MultiChoices[i] {
+				s += config.CheckedStyle.Render("  " + prefix + label)
+			} else {
+				s += config.MenuStyle.Render("  " + prefix + label)
+			}
+		}
+		s += "\n"
+	}
+
+	if len(m.MultiErrors) > 0 {
+		s += "\n" + config.ErrorStyle.Render("⚠️  Perfis indisponíveis:") + "\n"
+		for _, err := range m.MultiErrors {
+			s += config.ErrorStyle.Render(fmt.Sprintf("  • %s", err)) + "\n"
+		}
+	}
+
+	s += "\n" + config.TextStyle.Render(fmt.Sprintf("Selecionados: %d de %d bancos", selected, len(m.MultiTargets))) + "\n"
+	s += config.TextStyle.Render("[↑ ↓ ou J K] Navegar   [Espaço] Selecionar   [A] Todos do perfil   [Enter] Iniciar Backup   [Esc] Voltar") + "\n"
+
+	return s
+}
+
+// renderHostResults renders the summary of a multi-server run grouped by host
+func renderHostResults(m types.Model) string {
+	s := ""
+	for _, r := range m.MultiResults {
+		s += "\n" + config.TitleStyle.Render(fmt.Sprintf("  %s (%s)", r.Profile, r.Host)) + "\n"
+		s += config.SuccessStyle.Render(fmt.Sprintf("✓ Sucesso: %d", r.Success)) + "\n"
+		for _, filename := range r.Filenames {
+			s += config.TextStyle.Render(fmt.Sprintf("  • %s", filename)) + "\n"
+		}
+		if len(r.Errors) > 0 {
+			s += config.ErrorStyle.Render(fmt.Sprintf("✗ Erros: %d", len(r.Errors))) + "\n"
+			for _, err := range r.Errors {
+				s += config.ErrorStyle.Render(fmt.Sprintf("  • %s", err)) + "\n"
+			}
+		}
+	}
+	return s
+}
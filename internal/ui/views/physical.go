@@ -0,0 +1,98 @@
+package views
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RenderPhysicalConfirm renders the checks made before a base backup
+func RenderPhysicalConfirm(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+	conn := m.Connection()
+	s += config.TextStyle.Render("Backup físico (pg_basebackup)") + "\n\n"
+
+	if m.PhysicalChecking || m.PhysicalPlan == nil {
+		s += m.Spinner.View() + " Procurando pg_basebackup...\n"
+		return s
+	}
+	p := m.PhysicalPlan
+
+	s += config.TextStyle.Render(fmt.Sprintf("Servidor:              %s:%s (cluster inteiro)", conn.Host, conn.Port)) + "\n"
+	if p.Client != "" {
+		s += config.TextStyle.Render(fmt.Sprintf("pg_basebackup:         %s", p.Client)) + "\n"
+	}
+	if p.Dir != "" {
+		s += config.TextStyle.Render(fmt.Sprintf("Destino:               %s", p.Dir)) + "\n"
+	}
+	s += config.TextStyle.Render("Formato:               tar com gzip, WAL transmitido durante o backup") + "\n"
+	if p.WALArchiveDir != "" {
+		s += config.TextStyle.Render(fmt.Sprintf("Arquivo de WAL:        %s", p.WALArchiveDir)) + "\n"
+	}
+	if p.Keep > 0 {
+		s += config.TextStyle.Render(fmt.Sprintf("Retenção:              %d backup(s) físico(s) deste servidor", p.Keep)) + "\n"
+	}
+
+	s += "\n"
+	if p.Err != nil {
+		s += config.ErrorStyle.Render("⚠️  "+p.Err.Error()) + "\n\n"
+		s += config.TextStyle.Render("[Esc] Voltar   [Q] Sair") + "\n"
+		return s
+	}
+	s += config.TextStyle.Render("O usuário precisa do atributo REPLICATION e de uma entrada \"replication\" no pg_hba.conf.") + "\n\n"
+	s += config.TextStyle.Render("[Enter/Y] Iniciar Backup   [Esc] Voltar   [Q] Sair") + "\n"
+	return s
+}
+
+// RenderPhysicalProgress renders the base backup progress and result
+func RenderPhysicalProgress(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+
+	r := m.PhysicalResult
+	if m.PhysicalRunning || r == nil {
+		p := m.PhysicalProgress
+		s += config.TextStyle.Render("Executando pg_basebackup...") + "\n\n"
+		if p.TotalKB > 0 {
+			s += m.Spinner.View() + " " + progressBar(int(p.DoneKB), int(p.TotalKB), 40) +
+				fmt.Sprintf(" %s de %s", FormatBytes(p.DoneKB*1024), FormatBytes(p.TotalKB*1024)) + "\n\n"
+		} else {
+			s += m.Spinner.View() + " Aguardando o checkpoint do servidor...\n\n"
+		}
+		s += config.TextStyle.Render(fmt.Sprintf("Tempo decorrido: %s", time.Since(m.PhysicalStarted).Round(time.Second))) + "\n"
+		return s
+	}
+
+	if r.Err != nil {
+		s += config.ErrorStyle.Render("✗ Falha no backup físico") + "\n\n"
+		s += config.ErrorStyle.Render(r.Err.Error()) + "\n"
+	} else {
+		s += config.SuccessStyle.Render("✓ Backup Físico Concluído!") + "\n\n"
+		s += config.TextStyle.Render(fmt.Sprintf("Diretório: %s", filepath.Base(r.Path))) + "\n"
+		s += config.TextStyle.Render(fmt.Sprintf("Tamanho: %s", FormatBytes(r.Size))) + "\n"
+		s += config.TextStyle.Render(fmt.Sprintf("Duração: %s", r.FinishedAt.Sub(r.StartedAt).Round(time.Second))) + "\n"
+		if len(r.Removed) > 0 {
+			s += config.TextStyle.Render(fmt.Sprintf("Removidos pela retenção: %s", strings.Join(r.Removed, ", "))) + "\n"
+		}
+		if r.RemovedWAL > 0 {
+			s += config.TextStyle.Render(fmt.Sprintf("Arquivos de WAL removidos: %d", r.RemovedWAL)) + "\n"
+		}
+		if r.Warnings != "" {
+			s += "\n" + config.TextStyle.Render("Mensagens:") + "\n"
+			s += config.TextStyle.Render(r.Warnings) + "\n"
+		}
+	}
+
+	s += "\n" + config.TextStyle.Render("[Enter/Esc] Voltar ao Menu   [Q] Sair") + "\n"
+	return s
+}
@@ -0,0 +1,111 @@
+package views
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pitrPort is the port suggested for the recovered server, so it can run beside the original
+const pitrPort = 5433
+
+// RenderPITRSetup renders the base backup, recovery target and directories of a point-in-time recovery
+func RenderPITRSetup(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+	s += config.TextStyle.Render("Recuperação para um ponto no tempo (PITR)") + "\n\n"
+
+	b := m.PITRBackups[m.PITRBackup]
+	fields := []struct {
+		label string
+		value string
+	}{
+		{"Backup físico", fmt.Sprintf("◀ %s ▶", filepath.Base(b.Path))},
+		{"Alvo", m.PITRTarget.View()},
+		{"Diretório de dados", m.PITRDataDir.View()},
+		{"Arquivo de WAL", m.PITRWALDir.View()},
+	}
+	for i, f := range fields {
+		if i == m.PITRField {
+			s += config.SelectedStyle.Render(fmt.Sprintf("-➤ %-20s", f.label+":")) + " " + f.value + "\n"
+		} else {
+			s += config.MenuStyle.Render(fmt.Sprintf("  %-20s", f.label+":")) + " " + f.value + "\n"
+		}
+	}
+
+	s += "\n" + config.TextStyle.Render(fmt.Sprintf("Servidor: %s:%s (perfil %s)   Backup feito em %s (%d de %d)",
+		b.Host, b.Port, orDash(b.Profile), b.FinishedAt.Local().Format("02/01/2006 15:04:05"), m.PITRBackup+1, len(m.PITRBackups))) + "\n"
+	s += config.TextStyle.Render("O alvo deve ser posterior ao fim do backup; sem fuso, vale o horário local.") + "\n"
+
+	if m.PITRRunning {
+		s += "\n" + m.Spinner.View() + " Extraindo o backup físico...\n"
+	}
+	if m.PITRError != "" {
+		s += "\n" + config.ErrorStyle.Render("⚠️  "+m.PITRError) + "\n"
+	}
+
+	s += "\n" + config.TextStyle.Render("[Tab ↑ ↓] Campos   [← →] Alterar   [Enter] Preparar   [Esc] Voltar") + "\n"
+	return s
+}
+
+// RenderPITRResult renders the prepared data directory and the steps to run the recovery
+func RenderPITRResult(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+	r := m.PITRResult
+
+	s += config.SuccessStyle.Render("✓ Diretório de recuperação preparado!") + "\n\n"
+	target := r.Target
+	if target == "" {
+		target = "todo o WAL arquivado"
+	}
+	for _, row := range [][2]string{
+		{"Backup", filepath.Base(r.Backup)},
+		{"Diretório de dados", r.DataDir},
+		{"PostgreSQL", orDash(r.Version)},
+		{"Alvo", target},
+		{"Primeiro WAL", r.StartWAL},
+		{"WAL arquivado", fmt.Sprintf("%d segmento(s) em %s", r.ArchivedWAL, r.WALArchiveDir)},
+	} {
+		s += config.TextStyle.Render(fmt.Sprintf("%-19s %s", row[0]+":", row[1])) + "\n"
+	}
+	if r.ArchivedWAL == 0 {
+		s += "\n" + config.ErrorStyle.Render("⚠️  Nenhum segmento arquivado após o backup: a recuperação para no fim do backup.") + "\n"
+	}
+
+	pgCtl := "pg_ctl"
+	if r.Version != "" {
+		pgCtl = fmt.Sprintf("pg_ctl (PostgreSQL %s)", r.Version)
+	}
+	// Each step is a sentence and, optionally, the command to run
+	steps := [][2]string{
+		{fmt.Sprintf("Inicie o servidor com o %s, como o usuário dono do diretório:", pgCtl),
+			fmt.Sprintf("pg_ctl -D %s -o \"-p %d\" -l %s start", r.DataDir, pitrPort, filepath.Join(r.DataDir, "recovery.log"))},
+		{"Acompanhe o log até \"recovery stopping\" ou \"archive recovery complete\".", ""},
+		{"Confira os dados:", fmt.Sprintf("psql -p %d", pitrPort)},
+	}
+	if r.Target != "" {
+		steps = append(steps,
+			[2]string{"O servidor fica pausado no alvo. Se os dados estiverem corretos, promova com:", "SELECT pg_wal_replay_resume();"},
+			[2]string{"Se o alvo estiver errado, pare o servidor, apague o diretório e prepare de novo.", ""})
+	}
+
+	s += "\n" + config.TextStyle.Render("Próximos passos:") + "\n"
+	for i, step := range steps {
+		s += config.TextStyle.Render(fmt.Sprintf("%d. %s", i+1, step[0])) + "\n"
+		if step[1] != "" {
+			s += config.MenuStyle.Render("     "+step[1]) + "\n"
+		}
+	}
+	s += "\n" + config.TextStyle.Render("O archive_mode foi desligado no postgresql.auto.conf para não gravar no arquivo de WAL de origem.") + "\n"
+
+	s += "\n" + config.TextStyle.Render("[Enter/Esc] Voltar ao Menu   [Q] Sair") + "\n"
+	return s
+}
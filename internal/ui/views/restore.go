@@ -0,0 +1,297 @@
+package views
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// restoreListHeight is the number of rows shown at once in the restore lists
+const restoreListHeight = 15
+
+// RenderRestoreFile renders the archives available for restore
+func RenderRestoreFile(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+	if m.Inspecting {
+		s += config.TextStyle.Render("Inspecionar Backup - selecione o arquivo") + "\n\n"
+	} else {
+		s += config.TextStyle.Render("Restaurar Backup - selecione o arquivo") + "\n\n"
+	}
+
+	if m.RestorePathMode {
+		s += config.TextStyle.Render("Caminho do arquivo:") + "\n"
+		s += config.SearchInputActiveStyle.Render(m.RestorePathInput.View()) + "\n\n"
+		s += config.TextStyle.Render("[Enter] Abrir   [Esc] Cancelar") + "\n"
+		return s
+	}
+
+	s += config.TextStyle.Render(fmt.Sprintf("   %-45s %-20s %-16s %10s", "Arquivo", "Banco", "Data", "Tamanho")) + "\n"
+
+	start := max(0, m.RestoreCursor-restoreListHeight/2)
+	end := min(len(m.RestoreArchives), start+restoreListHeight)
+	start = max(0, end-restoreListHeight)
+
+	for i := start; i < end; i++ {
+		archive := m.RestoreArchives[i]
+		database := archive.Database
+		if database == "" {
+			database = "-"
+		} else if archive.Schema != "" {
+			database += "." + archive.Schema
+		}
+		label := fmt.Sprintf("%-45s %-20s %-16s %10s", truncate(filepath.Base(archive.Path), 45), truncate(database, 20),
+			archive.CreatedAt.Format("02/01/2006 15:04"), FormatBytes(archive.Size))
+
+		if i == m.RestoreCursor {
+			s += config.SelectedStyle.Render("-➤ " + label)
+		} else {
+			s += config.MenuStyle.Render("  " + label)
+		}
+		s += "\n"
+	}
+
+	s += "\n" + config.TextStyle.Render("[↑ ↓ ou J K] Navegar   [Enter] Abrir   [P] Informar caminho   [Esc] Voltar") + "\n"
+	return s
+}
+
+// RenderRestoreTOC renders the objects of an archive as a tree of schemas and their objects
+func RenderRestoreTOC(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+	s += config.TextStyle.Render(fmt.Sprintf("Restaurar de %s", filepath.Base(m.RestoreFile))) + "\n"
+
+	if m.RestoreLoading {
+		s += "\n" + m.Spinner.View() + " Lendo índice do arquivo...\n"
+		return s
+	}
+	if m.RestoreError != "" {
+		s += "\n" + config.ErrorStyle.Render("⚠️  "+m.RestoreError) + "\n\n"
+		s += config.TextStyle.Render("[Esc] Voltar") + "\n"
+		return s
+	}
+
+	h := m.RestoreHeader
+	s += config.TextStyle.Render(fmt.Sprintf("Banco: %s   Formato: %s   Servidor: %s   pg_dump: %s", h.Database, h.Format, h.DumpedFrom, h.DumpedBy)) + "\n\n"
+
+	if len(m.RestoreNodes) == 0 {
+		s += config.TextStyle.Render("O arquivo não contém objetos") + "\n\n"
+		s += config.TextStyle.Render("[Esc] Voltar") + "\n"
+		return s
+	}
+
+	start := max(0, m.RestoreCursor-restoreListHeight/2)
+	end := min(len(m.RestoreNodes), start+restoreListHeight)
+	start = max(0, end-restoreListHeight)
+
+	for i := start; i < end; i++ {
+		node := m.RestoreNodes[i]
+		checked := m.RestoreChoices[i]
+
+		prefix := "[ ] "
+		switch {
+		case checked:
+			prefix = "[x] "
+		case node.Parent < 0 && partiallySelected(m, i):
+			prefix = "[~] "
+		}
+
+		label := node.Label
+		if node.Parent >= 0 {
+			label = fmt.Sprintf("    %-50s %s", truncate(node.Label, 50), node.Kind)
+		}
+
+		if i == m.RestoreCursor {
+			if checked {
+				s += config.CheckedCursorStyle.Render("-➤ " + prefix + label)
+			} else {
+				s += config.SelectedStyle.Render("-➤ " + prefix + label)
+			}
+		} else {
+			if checked {
+				s += config.CheckedStyle.Render("  " + prefix + label)
+			} else {
+				s += config.MenuStyle.Render("  " + prefix + label)
+			}
+		}
+		s += "\n"
+	}
+
+	selected := 0
+	for i := range m.RestoreNodes {
+		if m.RestoreChoices[i] {
+			selected++
+		}
+	}
+	s += "\n" + config.TextStyle.Render(fmt.Sprintf("Selecionados: %d de %d itens", selected, len(m.RestoreNodes))) + "\n"
+	s += config.TextStyle.Render("[↑ ↓ ou J K] Navegar   [Espaço] Selecionar   [A] Todos   [I] Inspecionar   [Enter] Continuar   [Esc] Voltar") + "\n"
+	return s
+}
+
+// partiallySelected reports whether some objects of the schema row i are selected
+func partiallySelected(m types.Model, i int) bool {
+	for j, node := range m.RestoreNodes {
+		if node.Parent == i && m.RestoreChoices[j] {
+			return true
+		}
+	}
+	return false
+}
+
+// progressBar renders a text progress bar of the given width
+func progressBar(done, total, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = min(width, done*width/total)
+	}
+	percent := 0
+	if total > 0 {
+		percent = done * 100 / total
+	}
+	return fmt.Sprintf("%s%s %3d%%", strings.Repeat("█", filled), strings.Repeat("░", width-filled), percent)
+}
+
+// RenderRestoreConfirm renders the target database prompt and safety options shown before a restore
+func RenderRestoreConfirm(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+	s += config.TextStyle.Render("Confirmação da Restauração") + "\n\n"
+
+	selected := 0
+	for i := range m.RestoreNodes {
+		if m.RestoreChoices[i] {
+			selected++
+		}
+	}
+	conn := m.Connection()
+	s += config.TextStyle.Render(fmt.Sprintf("Arquivo:               %s", filepath.Base(m.RestoreFile))) + "\n"
+	s += config.TextStyle.Render(fmt.Sprintf("Itens selecionados:    %d", selected)) + "\n"
+	s += config.TextStyle.Render(fmt.Sprintf("Servidor:              %s:%s", conn.Host, conn.Port)) + "\n\n"
+
+	if m.Production && !m.AllowRestore {
+		s += config.ErrorStyle.Render(fmt.Sprintf("Restauração bloqueada: o perfil %s está marcado como produção.", m.ProfileName)) + "\n"
+		s += config.TextStyle.Render("Defina \"allow_restore\": true no perfil ou execute o snapTUI com --allow-restore.") + "\n\n"
+		s += config.TextStyle.Render("[Esc] Voltar") + "\n"
+		return s
+	}
+
+	status := m.RestoreStatus
+	if status == nil {
+		s += config.TextStyle.Render("Banco de destino (existente ou novo):") + "\n"
+		s += config.SearchInputActiveStyle.Render(m.RestoreTarget.View()) + "\n"
+		if m.RestoreChecking {
+			s += "\n" + m.Spinner.View() + " Verificando banco de destino...\n"
+		}
+		if m.RestoreError != "" {
+			s += "\n" + config.ErrorStyle.Render("⚠️  "+m.RestoreError) + "\n"
+		}
+		s += "\n" + config.TextStyle.Render("[Enter] Continuar   [Esc] Voltar") + "\n"
+		return s
+	}
+
+	jobs := fmt.Sprintf("Processos paralelos (--jobs): ◀ %d ▶", m.RestoreJobs)
+	switch m.RestoreHeader.Format {
+	case "CUSTOM", "DIRECTORY":
+	default:
+		jobs = fmt.Sprintf("Processos paralelos (--jobs): 1 (indisponível no formato %s)", m.RestoreHeader.Format)
+	}
+
+	if !status.Exists {
+		s += config.SuccessStyle.Render(fmt.Sprintf("O banco %s não existe e será criado antes da restauração.", status.Database)) + "\n\n"
+		s += config.SelectedStyle.Render("-➤ "+jobs) + "\n"
+		if m.RestoreError != "" {
+			s += "\n" + config.ErrorStyle.Render("⚠️  "+m.RestoreError) + "\n"
+		}
+		s += "\n" + config.TextStyle.Render("[← →] Processos   [Enter] Restaurar   [Esc] Voltar") + "\n"
+		return s
+	}
+
+	s += config.ErrorStyle.Render(fmt.Sprintf("⚠️  O banco %s já existe no servidor.", status.Database)) + "\n"
+	if status.Connections > 0 {
+		s += config.ErrorStyle.Render(fmt.Sprintf("⚠️  %d conexão(ões) ativa(s) no banco de destino.", status.Connections)) + "\n"
+	}
+	s += "\n"
+
+	checkbox := func(on bool) string {
+		if on {
+			return "[x] "
+		}
+		return "[ ] "
+	}
+	fields := []string{
+		jobs,
+		checkbox(m.RestoreSafety) + "Backup de segurança do banco antes de restaurar",
+		checkbox(m.RestoreClean) + "Remover objetos existentes antes de recriá-los (--clean --if-exists)",
+		fmt.Sprintf("Digite %s para confirmar: %s", status.Database, m.RestoreConfirm.View()),
+	}
+	for i, field := range fields {
+		if i == m.RestoreField {
+			s += config.SelectedStyle.Render("-➤ "+field) + "\n"
+		} else {
+			s += config.MenuStyle.Render("  "+field) + "\n"
+		}
+	}
+
+	if m.RestoreError != "" {
+		s += "\n" + config.ErrorStyle.Render("⚠️  "+m.RestoreError) + "\n"
+	}
+
+	s += "\n" + config.TextStyle.Render("[Tab ↑ ↓] Campos   [← →] Processos   [Espaço] Marcar   [Enter] Restaurar   [Esc] Voltar") + "\n"
+	return s
+}
+
+// RenderRestoreProgress renders the restore progress and result
+func RenderRestoreProgress(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+
+	r := m.RestoreResult
+	if m.Restoring || r == nil {
+		p := m.RestoreProgress
+		s += config.TextStyle.Render(fmt.Sprintf("Restaurando %s...", filepath.Base(m.RestoreFile))) + "\n\n"
+		if p.SafetyBackup {
+			s += m.Spinner.View() + " Fazendo backup de segurança do banco de destino...\n\n"
+		} else {
+			s += m.Spinner.View() + " " + progressBar(p.Done, p.Total, 40) + fmt.Sprintf(" %d de %d objetos", p.Done, p.Total) + "\n\n"
+			if p.Current != "" {
+				s += config.TextStyle.Render(fmt.Sprintf("Objeto atual:  %s", truncate(p.Current, 60))) + "\n"
+			}
+		}
+		s += config.TextStyle.Render(fmt.Sprintf("Tempo decorrido: %s", time.Since(m.RestoreStarted).Round(time.Second))) + "\n"
+		return s
+	}
+
+	if r.Err != nil {
+		s += config.ErrorStyle.Render("✗ Falha na restauração") + "\n\n"
+		s += config.ErrorStyle.Render(r.Err.Error()) + "\n"
+		if r.SafetyBackup != "" {
+			s += "\n" + config.TextStyle.Render(fmt.Sprintf("Backup de segurança: %s", r.SafetyBackup)) + "\n"
+		}
+	} else {
+		s += config.SuccessStyle.Render(fmt.Sprintf("✓ %d objeto(s) restaurado(s) em %s", r.Entries, r.Target)) + "\n"
+		if r.SafetyBackup != "" {
+			s += config.TextStyle.Render(fmt.Sprintf("Backup de segurança: %s", r.SafetyBackup)) + "\n"
+		}
+		s += config.TextStyle.Render(fmt.Sprintf("Duração: %s", r.FinishedAt.Sub(r.StartedAt).Round(time.Second))) + "\n"
+		if r.Warnings != "" {
+			s += "\n" + config.TextStyle.Render("Mensagens do pg_restore:") + "\n"
+			s += config.TextStyle.Render(r.Warnings) + "\n"
+		}
+	}
+
+	s += "\n" + config.TextStyle.Render("[Enter/Esc] Voltar   [Q] Sair") + "\n"
+	return s
+}
@@ -0,0 +1,76 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RenderSchemaList renders the schemas of a database for per-schema backups
+func RenderSchemaList(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+	s += config.TextStyle.Render(fmt.Sprintf("Backup por esquema de %s", m.SchemaDatabase)) + "\n\n"
+
+	if m.LoadingSchemas {
+		s += m.Spinner.View() + " Carregando esquemas...\n"
+		return s
+	}
+	if m.SchemaError != "" {
+		s += config.ErrorStyle.Render("⚠️  "+m.SchemaError) + "\n\n"
+		s += config.TextStyle.Render("[Esc] Voltar") + "\n"
+		return s
+	}
+	if len(m.Schemas) == 0 {
+		s += config.TextStyle.Render("Nenhum esquema encontrado") + "\n\n"
+		s += config.TextStyle.Render("[Esc] Voltar") + "\n"
+		return s
+	}
+
+	s += config.TextStyle.Render(fmt.Sprintf("      %-40s %-20s %8s %10s", "Esquema", "Dono", "Tabelas", "Tamanho")) + "\n"
+
+	// Scroll window around the cursor
+	start := max(0, m.SchemaCursor-tableListHeight/2)
+	end := min(len(m.Schemas), start+tableListHeight)
+	start = max(0, end-tableListHeight)
+
+	selected := 0
+	for _, ok := range m.SchemaChoices {
+		if ok {
+			selected++
+		}
+	}
+
+	for i := start; i < end; i++ {
+		sc := m.Schemas[i]
+		prefix := "[ ] "
+		if m.SchemaChoices[i] {
+			prefix = "[x] "
+		}
+		label := fmt.Sprintf("%-40s %-20s %8d %10s", truncate(sc.Name, 40), truncate(sc.Owner, 20), sc.Tables, FormatBytes(sc.Size))
+
+		if i == m.SchemaCursor {
+			if m.SchemaChoices[i] {
+				s += config.CheckedCursorStyle.Render("-➤ " + prefix + label)
+			} else {
+				s += config.SelectedStyle.Render("-➤ " + prefix + label)
+			}
+		} else {
+			if m.SchemaChoices[i] {
+				s += config.CheckedStyle.Render("  " + prefix + label)
+			} else {
+				s += config.MenuStyle.Render("  " + prefix + label)
+			}
+		}
+		s += "\n"
+	}
+
+	s += "\n" + config.TextStyle.Render(fmt.Sprintf("Selecionados: %d de %d esquemas (um arquivo por esquema)", selected, len(m.Schemas))) + "\n"
+	s += config.TextStyle.Render("[↑ ↓ ou J K] Navegar   [Espaço] Selecionar   [A] Todos   [Enter] Fazer Backup   [Esc] Voltar") + "\n"
+
+	return s
+}
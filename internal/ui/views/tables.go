@@ -0,0 +1,167 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/Luiz-F3lipe/snapTUI/internal/config"
+	"github.com/Luiz-F3lipe/snapTUI/internal/types"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tableListHeight is the number of rows shown at once in the table list
+const tableListHeight = 15
+
+// tableKindLabels describes pg_class.relkind values
+var tableKindLabels = map[string]string{
+	"r": "tabela",
+	"p": "particionada",
+	"v": "view",
+	"m": "view mat.",
+	"f": "externa",
+}
+
+// exportFormatLabels names the per-table export formats
+var exportFormatLabels = map[string]string{
+	"csv":     "CSV",
+	"jsonl":   "JSON Lines",
+	"parquet": "Parquet",
+}
+
+// RenderTableList renders the tables of a database for per-table export
+func RenderTableList(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+	s += config.TextStyle.Render(fmt.Sprintf("Exportar tabelas de %s", m.TableDatabase)) + "\n\n"
+
+	if m.LoadingTables {
+		s += m.Spinner.View() + " Carregando tabelas...\n"
+		return s
+	}
+	if m.TableError != "" {
+		s += config.ErrorStyle.Render("⚠️  "+m.TableError) + "\n\n"
+		s += config.TextStyle.Render("[Esc] Voltar") + "\n"
+		return s
+	}
+	if len(m.Tables) == 0 {
+		s += config.TextStyle.Render("Nenhuma tabela encontrada") + "\n\n"
+		s += config.TextStyle.Render("[Esc] Voltar") + "\n"
+		return s
+	}
+
+	s += config.TextStyle.Render(fmt.Sprintf("      %-40s %-12s %12s %10s", "Tabela", "Tipo", "Linhas (est.)", "Tamanho")) + "\n"
+
+	// Scroll window around the cursor
+	start := max(0, m.TableCursor-tableListHeight/2)
+	end := min(len(m.Tables), start+tableListHeight)
+	start = max(0, end-tableListHeight)
+
+	selected := 0
+	for _, ok := range m.TableChoices {
+		if ok {
+			selected++
+		}
+	}
+
+	for i := start; i < end; i++ {
+		t := m.Tables[i]
+		prefix := "[ ] "
+		if m.TableChoices[i] {
+			prefix = "[x] "
+		}
+
+		rows := "-"
+		if t.Rows >= 0 {
+			rows = fmt.Sprintf("%d", t.Rows)
+		}
+		label := fmt.Sprintf("%-40s %-12s %12s %10s", truncate(t.QualifiedName(), 40), tableKindLabels[t.Kind], rows, FormatBytes(t.Size))
+
+		if i == m.TableCursor {
+			if m.TableChoices[i] {
+				s += config.CheckedCursorStyle.Render("-➤ " + prefix + label)
+			} else {
+				s += config.SelectedStyle.Render("-➤ " + prefix + label)
+			}
+		} else {
+			if m.TableChoices[i] {
+				s += config.CheckedStyle.Render("  " + prefix + label)
+			} else {
+				s += config.MenuStyle.Render("  " + prefix + label)
+			}
+		}
+		s += "\n"
+	}
+
+	s += "\n" + config.TextStyle.Render(fmt.Sprintf("Selecionadas: %d de %d tabelas", selected, len(m.Tables))) + "\n"
+	s += config.TextStyle.Render("[↑ ↓ ou J K] Navegar   [Espaço] Selecionar   [A] Todas   [Enter] Exportar   [Esc] Voltar") + "\n"
+
+	return s
+}
+
+// RenderTableExport renders the per-table export options and results
+func RenderTableExport(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+
+	selected := 0
+	for _, ok := range m.TableChoices {
+		if ok {
+			selected++
+		}
+	}
+	s += config.TextStyle.Render(fmt.Sprintf("Exportar %d tabela(s) de %s", selected, m.TableDatabase)) + "\n\n"
+
+	if m.Exporting {
+		s += m.Spinner.View() + " Exportando tabelas...\n"
+		return s
+	}
+
+	if r := m.ExportResult; r != nil {
+		if len(r.Files) > 0 {
+			s += config.SuccessStyle.Render(fmt.Sprintf("✓ %d arquivo(s), %d linha(s) exportada(s)", len(r.Files), r.Rows)) + "\n"
+			for _, file := range r.Files {
+				s += config.TextStyle.Render(fmt.Sprintf("  • %s", file)) + "\n"
+			}
+		}
+		if len(r.Errors) > 0 {
+			s += "\n" + config.ErrorStyle.Render(fmt.Sprintf("✗ Erros: %d", len(r.Errors))) + "\n"
+			for _, err := range r.Errors {
+				s += config.ErrorStyle.Render(fmt.Sprintf("  • %s", err)) + "\n"
+			}
+		}
+		s += "\n" + config.TextStyle.Render("Pressione qualquer tecla para voltar") + "\n"
+		return s
+	}
+
+	// Format selector
+	format := fmt.Sprintf("◀ %s ▶", exportFormatLabels[m.ExportFormat])
+	fields := []struct {
+		label string
+		value string
+	}{
+		{"Formato", format},
+		{"Filtro WHERE", m.ExportWhere.View()},
+		{"Limite de linhas", m.ExportLimit.View()},
+	}
+	for i, f := range fields {
+		if i == m.ExportField {
+			s += config.SelectedStyle.Render(fmt.Sprintf("-➤ %-18s", f.label+":")) + " " + f.value + "\n"
+		} else {
+			s += config.MenuStyle.Render(fmt.Sprintf("  %-18s", f.label+":")) + " " + f.value + "\n"
+		}
+	}
+
+	if m.Masking != "" {
+		s += "\n" + config.TextStyle.Render(fmt.Sprintf("Mascaramento: %s (as colunas sensíveis são substituídas nos arquivos)", m.Masking)) + "\n"
+	}
+
+	if m.ExportError != "" {
+		s += "\n" + config.ErrorStyle.Render("⚠️  "+m.ExportError) + "\n"
+	}
+
+	s += "\n" + config.TextStyle.Render("[Tab ↑ ↓] Campos   [← →] Formato   [Enter] Exportar   [Esc] Voltar") + "\n"
+	return s
+}
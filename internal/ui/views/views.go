@@ -2,6 +2,7 @@ package views
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/Luiz-F3lipe/snapTUI/internal/config"
 	"github.com/Luiz-F3lipe/snapTUI/internal/types"
@@ -36,25 +37,33 @@ func RenderConnection(m types.Model) string {
 
 	s := centeredTitle + "\n\n"
 	s += config.TextStyle.Render("Configuração de Conexão PostgreSQL") + "\n\n"
-
-	labels := []string{"Host:", "Port:", "User:", "Password:", "Database:"}
+	if m.SSHBastion != "" {
+		s += config.TextStyle.Render("🔒 Via túnel SSH: "+m.SSHBastion) + "\n\n"
+	}
 
 	formPadding := "  " // Left padding for the form
 
+	if m.URIMode {
+		s += formPadding + config.TextStyle.Render("Connection string (postgres://... ou chave=valor):") + "\n"
+		s += formPadding + config.SelectedInputStyle.Width(70).Render(m.URIInput.View()) + "\n\n"
+		if m.ConnectionError != "" {
+			s += config.ErrorStyle.Render("⚠️  "+m.ConnectionError) + "\n\n"
+		}
+		s += "\n[Enter] Aplicar   [Esc] Voltar aos campos   [Ctrl+C] Sair\n"
+		return s
+	}
+
+	labels := []string{"Host:", "Port:", "User:", "Password:", "Database:"}
+
 	for i, label := range labels {
 		s += formPadding + config.TextStyle.Render(label) + "\n"
 		if i == m.InputField {
-			s += formPadding + config.SelectedInputStyle.Render(m.Inputs[i])
+			s += formPadding + config.SelectedInputStyle.Render(m.Inputs[i].View())
 		} else {
-			// Mask password
-			value := m.Inputs[i]
-			if i == 3 && value != "" { // Password field
-				value = string(make([]byte, len(value)))
-				for j := range value {
-					value = value[:j] + "*" + value[j+1:]
-				}
-			}
-			s += formPadding + config.InputStyle.Render(value)
+			s += formPadding + config.InputStyle.Render(m.Inputs[i].View())
+		}
+		if err := m.Inputs[i].Err; err != nil {
+			s += config.ErrorStyle.Render(err.Error())
 		}
 		s += "\n\n"
 	}
@@ -64,7 +73,17 @@ func RenderConnection(m types.Model) string {
 		s += config.ErrorStyle.Render("⚠️  "+m.ConnectionError) + "\n\n"
 	}
 
-	s += "\n[↑ ↓ tab] Navegar   [Espaço] Limpar   [Enter] Conectar   [Esc] Menu   [Q] Sair\n"
+	if m.ExportedURI != "" {
+		s += formPadding + config.TextStyle.Render("URI: "+m.ExportedURI) + "\n\n"
+	}
+
+	if m.Connecting {
+		s += formPadding + m.Spinner.View() + config.TextStyle.Render(fmt.Sprintf("Conectando a %s:%s...", m.Inputs[types.InputHost].Value(), m.Inputs[types.InputPort].Value())) + "\n\n"
+		s += "\n[Esc] Cancelar   [Ctrl+C] Sair\n"
+		return s
+	}
+
+	s += "\n[↑ ↓ tab] Navegar   [← →] Cursor   [Enter] Conectar   [Ctrl+U] Connection string   [Ctrl+E] Exportar URI   [Esc] Menu   [Ctrl+C] Sair\n"
 
 	return s
 }
@@ -91,6 +110,10 @@ func RenderDatabaseList(m types.Model) string {
 	// Get current page databases
 	currentPageDatabases := getCurrentPageDatabases(m)
 
+	// Column header
+	sortLabels := []string{"nome", "tamanho", "último backup"}
+	s += config.TextStyle.Render(fmt.Sprintf("      %s", formatDatabaseColumns("Banco", "Tamanho", "Dono", "Encoding", "Conexões", "Último backup"))) + "\n"
+
 	// Show current database list
 	for i, db := range currentPageDatabases {
 		// Find original index for checking selections
@@ -124,17 +147,18 @@ func RenderDatabaseList(m types.Model) string {
 			}
 		}
 
+		label := formatDatabaseRow(m, db, originalIndex)
 		if i == m.Cursor {
 			if isChecked {
-				s += config.CheckedCursorStyle.Render("-➤ " + prefix + db)
+				s += config.CheckedCursorStyle.Render("-➤ " + prefix + label)
 			} else {
-				s += config.SelectedStyle.Render("-➤ " + prefix + db)
+				s += config.SelectedStyle.Render("-➤ " + prefix + label)
 			}
 		} else {
 			if isChecked {
-				s += config.CheckedStyle.Render("  " + prefix + db)
+				s += config.CheckedStyle.Render("  " + prefix + label)
 			} else {
-				s += config.MenuStyle.Render("  " + prefix + db)
+				s += config.MenuStyle.Render("  " + prefix + label)
 			}
 		}
 		s += "\n"
@@ -142,21 +166,83 @@ func RenderDatabaseList(m types.Model) string {
 
 	// Pagination info and controls
 	s += "\n"
+	s += config.TextStyle.Render(fmt.Sprintf("Ordenado por: %s", sortLabels[m.SortBy])) + "\n"
 	if len(m.FilteredDatabases) > m.Paginator.PerPage {
 		currentStart := m.Paginator.Page*m.Paginator.PerPage + 1
 		currentEnd := min(m.Paginator.Page*m.Paginator.PerPage+m.Paginator.PerPage, len(m.FilteredDatabases))
 
 		s += config.TextStyle.Render(fmt.Sprintf("📄 Página %d de %d  |  Mostrando %d-%d de %d bancos",
 			m.Paginator.Page+1, m.Paginator.TotalPages, currentStart, currentEnd, len(m.FilteredDatabases))) + "\n\n"
-		s += config.TextStyle.Render("[← → ou H L] Páginas   [↑ ↓ ou J K] Navegar   [Espaço] Selecionar   [/] Pesquisar   [Enter] Confirmar   [Esc] Voltar") + "\n"
+		s += config.TextStyle.Render("[← → ou H L] Páginas   [↑ ↓ ou J K] Navegar   [Espaço] Selecionar   [/] Pesquisar   [S] Ordenar   [T] Tabelas   [E] Esquemas   [Enter] Confirmar   [Esc] Voltar") + "\n"
 	} else {
 		s += config.TextStyle.Render(fmt.Sprintf("Total: %d bancos", len(m.FilteredDatabases))) + "\n"
-		s += config.TextStyle.Render("[↑ ↓ ou J K] Navegar   [Espaço] Selecionar   [/] Pesquisar   [Enter] Confirmar   [Esc] Voltar") + "\n"
+		s += config.TextStyle.Render("[↑ ↓ ou J K] Navegar   [Espaço] Selecionar   [/] Pesquisar   [S] Ordenar   [T] Tabelas   [E] Esquemas   [Enter] Confirmar   [Esc] Voltar") + "\n"
 	}
 
 	return s
 }
 
+// formatDatabaseRow renders a database and its metadata as aligned columns
+func formatDatabaseRow(m types.Model, db string, originalIndex int) string {
+	if originalIndex == 0 {
+		// "All Databases" shows the total size of the server
+		var total int64
+		for _, info := range m.DatabaseInfo {
+			if info.Size > 0 {
+				total += info.Size
+			}
+		}
+		return formatDatabaseColumns(db, FormatBytes(total), "", "", "", "")
+	}
+
+	info, ok := m.DatabaseInfo[db]
+	if !ok {
+		return db
+	}
+
+	lastBackup := "nunca"
+	if !info.LastBackup.IsZero() {
+		lastBackup = info.LastBackup.Format("02/01/2006 15:04")
+	}
+
+	return formatDatabaseColumns(info.Name, FormatBytes(info.Size), info.Owner, info.Encoding,
+		fmt.Sprintf("%d", info.Connections), lastBackup)
+}
+
+// formatDatabaseColumns aligns the database list columns
+func formatDatabaseColumns(name, size, owner, encoding, connections, lastBackup string) string {
+	return fmt.Sprintf("%-28s %10s  %-14s %-9s %8s  %s",
+		truncate(name, 28), size, truncate(owner, 14), encoding, connections, lastBackup)
+}
+
+// truncate shortens s to at most n runes
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// FormatBytes renders a byte count in human readable units
+func FormatBytes(size int64) string {
+	if size < 0 {
+		return "-"
+	}
+
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
 // getCurrentPageDatabases returns the databases for the current page (helper for views)
 func getCurrentPageDatabases(m types.Model) []string {
 	totalItems := len(m.FilteredDatabases)
@@ -198,6 +284,86 @@ func min(a, b int) int {
 	return b
 }
 
+// RenderBackupConfirm renders the pre-flight check shown before a backup run
+func RenderBackupConfirm(m types.Model) string {
+	// Título centralizado
+	centeredTitle := lipgloss.PlaceHorizontal(config.TitleWidth, lipgloss.Center, config.TitleStyle.Render(config.Title))
+
+	s := centeredTitle + "\n\n"
+	p := m.Preflight
+
+	s += config.TextStyle.Render("Confirmação do Backup") + "\n\n"
+	if m.BackupSchemas != nil {
+		s += config.TextStyle.Render(fmt.Sprintf("Esquemas selecionados: %d de %s (um arquivo por esquema)", p.Databases, m.SchemaDatabase)) + "\n"
+	} else {
+		s += config.TextStyle.Render(fmt.Sprintf("Bancos selecionados:   %d", p.Databases)) + "\n"
+	}
+	s += config.TextStyle.Render(fmt.Sprintf("Tamanho de origem:     %s", FormatBytes(p.SourceSize))) + "\n"
+	s += config.TextStyle.Render(fmt.Sprintf("Tamanho estimado:      %s", FormatBytes(p.EstimatedSize))) + "\n"
+	s += config.TextStyle.Render(fmt.Sprintf("Espaço livre:          %s", FormatBytes(p.FreeSpace))) + "\n"
+	s += config.TextStyle.Render(fmt.Sprintf("Duração estimada:      %s", p.EstimatedDuration.Round(time.Second))) + "\n"
+	if p.Destination != "" {
+		s += config.TextStyle.Render(fmt.Sprintf("Destino:               %s", p.Destination)) + "\n"
+	}
+	if p.ServerVersion != "" {
+		s += config.TextStyle.Render(fmt.Sprintf("Servidor:              PostgreSQL %s", p.ServerVersion)) + "\n"
+	}
+	if p.PgDump != "" {
+		s += config.TextStyle.Render(fmt.Sprintf("pg_dump:               %s", p.PgDump)) + "\n"
+	}
+	if p.UnknownSizes > 0 {
+		s += "\n" + config.TextStyle.Render(fmt.Sprintf("%d banco(s) sem permissão para leitura do tamanho não entraram na estimativa", p.UnknownSizes)) + "\n"
+	}
+
+	if len(m.MaskingProfiles) > 0 && m.BackupSchemas == nil {
+		masking := "nenhum"
+		if m.Masking != "" {
+			masking = m.Masking
+		}
+		s += config.TextStyle.Render(fmt.Sprintf("Mascaramento:          %s", masking)) + "\n"
+	}
+
+	if p.Warning != "" {
+		s += "\n" + config.ErrorStyle.Render("⚠️  "+p.Warning) + "\n"
+	}
+
+	s += "\n"
+	switch {
+	case p.OutOfSpace:
+		s += config.ErrorStyle.Render("Backup bloqueado. Libere espaço no destino ou selecione menos bancos.") + "\n\n"
+		s += config.TextStyle.Render("[Esc] Voltar   [Q] Sair") + "\n"
+	case m.BackupSchemas != nil && m.Masking != "":
+		s += config.ErrorStyle.Render(fmt.Sprintf("O perfil usa o mascaramento %s, que o backup por esquema não aplica: o pg_dump gravaria os dados sem máscara.", m.Masking)) + "\n"
+		s += config.TextStyle.Render("Desligue o mascaramento para fazer um backup sem máscara, ou use a exportação de tabelas, que aplica as regras.") + "\n\n"
+		s += config.TextStyle.Render("[M] Desligar mascaramento   [Esc] Voltar   [Q] Sair") + "\n"
+	case m.BackupSchemas != nil && p.Blocked:
+		// The logical export has no per-schema mode, so there is no fallback
+		s += config.ErrorStyle.Render("Backup bloqueado. Instale um pg_dump compatível com a versão do servidor.") + "\n\n"
+		s += config.TextStyle.Render("[Esc] Voltar   [Q] Sair") + "\n"
+	case m.BackupSchemas != nil:
+		s += config.TextStyle.Render("[Enter/Y] Iniciar Backup   [Esc] Voltar   [Q] Sair") + "\n"
+	case m.Masking != "":
+		s += config.TextStyle.Render("Com mascaramento, os bancos são gravados pela exportação lógica, com as colunas sensíveis substituídas e um relatório ao lado do arquivo.") + "\n\n"
+		s += config.TextStyle.Render("[Enter/Y] Iniciar Exportação mascarada   [M] Mascaramento   [Esc] Voltar   [Q] Sair") + "\n"
+	case p.Blocked && p.PgDump == "":
+		s += config.ErrorStyle.Render("Backup bloqueado. Instale um pg_dump compatível com a versão do servidor.") + "\n"
+		s += config.TextStyle.Render("Como alternativa, a exportação lógica grava as tabelas em CSV com a descrição do esquema em um único arquivo .tar.gz.") + "\n\n"
+		s += config.TextStyle.Render("[L] Exportação lógica   "+maskingKey(m)+"[Esc] Voltar   [Q] Sair") + "\n"
+	default:
+		s += config.TextStyle.Render("[Enter/Y] Iniciar Backup   "+maskingKey(m)+"[Esc] Voltar   [Q] Sair") + "\n"
+	}
+
+	return s
+}
+
+// maskingKey returns the masking key hint, shown only when masking profiles are configured
+func maskingKey(m types.Model) string {
+	if len(m.MaskingProfiles) == 0 {
+		return ""
+	}
+	return "[M] Mascaramento   "
+}
+
 // RenderBackupProgress renders the backup progress and results screen
 func RenderBackupProgress(m types.Model) string {
 	// Título centralizado
@@ -226,21 +392,30 @@ func RenderBackupProgress(m types.Model) string {
 		s += config.TextStyle.Render("═══════════════════════════════════════") + "\n\n"
 
 		s += config.SuccessStyle.Render(fmt.Sprintf("✓ Backups realizados com sucesso: %d", m.BackupSuccess)) + "\n"
-
-		if len(m.BackupFilenames) > 0 {
+		if m.MultiRun {
+			s += config.ErrorStyle.Render(fmt.Sprintf("✗ Erros encontrados: %d", len(m.BackupErrors))) + "\n"
+			s += renderHostResults(m)
+		} else if len(m.BackupFilenames) > 0 {
 			s += "\n" + config.TextStyle.Render("Arquivos criados:") + "\n"
 			for _, filename := range m.BackupFilenames {
 				s += config.TextStyle.Render(fmt.Sprintf("  • %s", filename)) + "\n"
 			}
 		}
 
-		if len(m.BackupErrors) > 0 {
+		if len(m.BackupErrors) > 0 && !m.MultiRun {
 			s += "\n" + config.ErrorStyle.Render(fmt.Sprintf("✗ Erros encontrados: %d", len(m.BackupErrors))) + "\n"
 			for _, err := range m.BackupErrors {
 				s += config.ErrorStyle.Render(fmt.Sprintf("  • %s", err)) + "\n"
 			}
 		}
 
+		if len(m.NotificationErrors) > 0 {
+			s += "\n" + config.ErrorStyle.Render("⚠️  Falha ao enviar notificações:") + "\n"
+			for _, err := range m.NotificationErrors {
+				s += config.ErrorStyle.Render(fmt.Sprintf("  • %s", err)) + "\n"
+			}
+		}
+
 		s += "\n" + config.TextStyle.Render("═══════════════════════════════════════") + "\n\n"
 		s += config.TextStyle.Render("[Enter/Esc] Voltar ao Menu   [Q] Sair") + "\n"
 	}